@@ -0,0 +1,108 @@
+package ics
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// set_op is a truth table for a binary set operation. Bit (a | b<<1) is set if
+// a value that is contained in the first operand (a) and/or in the second
+// operand (b) is contained in the result.
+type set_op uint8
+
+const (
+	op_union        set_op = 0b1110
+	op_intersection set_op = 0b1000
+	op_difference   set_op = 0b0010
+	op_symmetric    set_op = 0b0110
+)
+
+func (op set_op) eval(a, b bool) bool {
+	i := 0
+	if a {
+		i |= 1
+	}
+	if b {
+		i |= 2
+	}
+	return op&(1<<i) != 0
+}
+
+// combine walks the boundaries of a and b in a single merge pass and appends
+// the boundaries of the combined set to dst[:0].
+//
+// Crossing a boundary toggles containment within its set, so the containment
+// state after visiting a[:i] and b[:j] is given by the parity of i and j. This
+// also takes care of the open-ended tails: an odd number of boundaries leaves
+// the state toggled on forever.
+func combine[S ~[]T, T constraints.Ordered](dst, a, b S, op set_op) S {
+	dst = dst[:0]
+	i, j, na, nb := 0, 0, len(a), len(b)
+	in := false
+	for i < na || j < nb {
+		var v T
+		if j == nb || (i < na && a[i] < b[j]) {
+			v = a[i]
+			i++
+		} else if i == na || b[j] < a[i] {
+			v = b[j]
+			j++
+		} else {
+			v = a[i]
+			i++
+			j++
+		}
+		if op.eval(i&1 == 1, j&1 == 1) != in {
+			in = !in
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
+
+// Union returns a set that contains all elements contained in a or b.
+func Union[S ~[]T, T constraints.Ordered](a, b S) S {
+	return combine(nil, a, b, op_union)
+}
+
+// UnionInto is similar to Union, but it reuses the storage of dst for the
+// result. The dst set must not share its storage with a or b.
+func UnionInto[S ~[]T, T constraints.Ordered](dst, a, b S) S {
+	return combine(dst, a, b, op_union)
+}
+
+// Intersect returns a set that contains the elements contained in both a and
+// b.
+func Intersect[S ~[]T, T constraints.Ordered](a, b S) S {
+	return combine(nil, a, b, op_intersection)
+}
+
+// IntersectInto is similar to Intersect, but it reuses the storage of dst for
+// the result. The dst set must not share its storage with a or b.
+func IntersectInto[S ~[]T, T constraints.Ordered](dst, a, b S) S {
+	return combine(dst, a, b, op_intersection)
+}
+
+// Difference returns a set that contains the elements contained in a, but not
+// in b.
+func Difference[S ~[]T, T constraints.Ordered](a, b S) S {
+	return combine(nil, a, b, op_difference)
+}
+
+// DifferenceInto is similar to Difference, but it reuses the storage of dst
+// for the result. The dst set must not share its storage with a or b.
+func DifferenceInto[S ~[]T, T constraints.Ordered](dst, a, b S) S {
+	return combine(dst, a, b, op_difference)
+}
+
+// SymmetricDifference returns a set that contains the elements contained in
+// either a or b, but not in both.
+func SymmetricDifference[S ~[]T, T constraints.Ordered](a, b S) S {
+	return combine(nil, a, b, op_symmetric)
+}
+
+// SymmetricDifferenceInto is similar to SymmetricDifference, but it reuses the
+// storage of dst for the result. The dst set must not share its storage with a
+// or b.
+func SymmetricDifferenceInto[S ~[]T, T constraints.Ordered](dst, a, b S) S {
+	return combine(dst, a, b, op_symmetric)
+}
@@ -0,0 +1,83 @@
+package ics
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func random_byteset(n int) (s byteset) {
+	for i := 0; i < n; i++ {
+		l, h := byte(rand.Intn(256)), byte(rand.Intn(256))
+		if h < l || rand.Intn(8) == 0 {
+			InsertInterval(&s, l, l)
+		} else if l < h {
+			InsertInterval(&s, l, h)
+		}
+	}
+	return
+}
+
+// random_int8set produces a set of up to four random intervals, which may
+// overlap or be open-ended.
+func random_int8set(r *rand.Rand) Set[int8] {
+	b := Builder[int8]{}
+	for j := r.Intn(5); j > 0; j-- {
+		b.Add(int8(r.Intn(256)-128), int8(r.Intn(256)-128))
+	}
+	return b.Build()
+}
+
+func TestSetAlgebra(t *testing.T) {
+	ops := []struct {
+		name string
+		f    func(a, b byteset) byteset
+		want func(a, b bool) bool
+	}{
+		{"Union", Union[byteset], func(a, b bool) bool { return a || b }},
+		{"Intersect", Intersect[byteset], func(a, b bool) bool { return a && b }},
+		{"Difference", Difference[byteset], func(a, b bool) bool { return a && !b }},
+		{"SymmetricDifference", SymmetricDifference[byteset], func(a, b bool) bool { return a != b }},
+	}
+
+	for iter := 0; iter < 500; iter++ {
+		a := random_byteset(rand.Intn(6))
+		b := random_byteset(rand.Intn(6))
+		for _, op := range ops {
+			got := op.f(a, b)
+			for i := 0; i+1 < len(got); i++ {
+				if got[i] >= got[i+1] {
+					t.Fatalf("%s(%v, %v) = %v is not sorted", op.name, a, b, got)
+				}
+			}
+			for v := 0; v < 256; v++ {
+				want := op.want(Contains(a, byte(v)), Contains(b, byte(v)))
+				if Contains(got, byte(v)) != want {
+					t.Fatalf("%s(%v, %v) = %v, containment of %d should be %v", op.name, a, b, got, v, want)
+				}
+			}
+		}
+	}
+}
+
+func TestSetAlgebraInto(t *testing.T) {
+	a := byteset{1, 5, 10}
+	b := byteset{3, 12}
+	dst := make(byteset, 0, 8)
+
+	got := UnionInto(dst, a, b)
+	if got.String() != "[1..." {
+		t.Errorf("UnionInto = %v, want [1...", got)
+	}
+	if &got[0] != &dst[:1][0] {
+		t.Errorf("UnionInto did not reuse dst")
+	}
+	if got = IntersectInto(dst, a, b); got.String() != "[3,5)[10,12)" {
+		t.Errorf("IntersectInto = %v, want [3,5)[10,12)", got)
+	}
+	if got = DifferenceInto(dst, a, b); got.String() != "[1,3)[12..." {
+		t.Errorf("DifferenceInto = %v, want [1,3)[12...", got)
+	}
+	if got = SymmetricDifferenceInto(dst, a, b); got.String() != "[1,3)[5,10)[12..." {
+		t.Errorf("SymmetricDifferenceInto = %v, want [1,3)[5,10)[12...", got)
+	}
+}
@@ -615,3 +615,146 @@ func Test_Join(t *testing.T) {
 	join(45, 45, "[10,20)[30,40)")
 	join(45, 50, "[10,20)[30,40)")
 }
+
+func Test_Remove(t *testing.T) {
+	var cs byteset
+
+	check := func(name string, cs byteset, want string) {
+		got := cs.String()
+		if got != want {
+			t.Errorf("%s got %s, want %s", name, got, want)
+		}
+	}
+	remove := func(l, h byte, want string) {
+		var name string
+		if l < h {
+			name = fmt.Sprintf("Remove [%v,%v) from %v", l, h, cs)
+		} else {
+			name = fmt.Sprintf("Remove [%v... from %v", l, cs)
+		}
+		tmp := slices.Clone(cs)
+		RemoveInterval(&tmp, l, h)
+		check(name, tmp, want)
+	}
+
+	// ------------------------------
+	// TARGET: empty
+	// ------------------------------
+	cs = byteset{}
+	remove(5, 5, "")
+	remove(5, 8, "")
+
+	// ------------------------------
+	// TARGET: a single open interval
+	// ------------------------------
+	cs = byteset{10}
+	remove(0, 0, "")
+	remove(10, 10, "")
+	remove(20, 20, "[10,20)")
+	remove(0, 5, "[10...")
+	remove(0, 10, "[10...")
+	remove(0, 15, "[15...")
+	remove(10, 15, "[15...")
+	remove(15, 20, "[10,15)[20...")
+
+	// ------------------------------
+	// TARGET: a bounded interval followed by an open interval
+	// ------------------------------
+	cs = byteset{10, 20, 30}
+	remove(0, 0, "")
+	remove(15, 15, "[10,15)")
+	remove(20, 20, "[10,20)")
+	remove(25, 25, "[10,20)")
+	remove(35, 35, "[10,20)[30,35)")
+	remove(0, 10, "[10,20)[30...")
+	remove(0, 15, "[15,20)[30...")
+	remove(10, 20, "[30...")
+	remove(12, 18, "[10,12)[18,20)[30...")
+	remove(15, 30, "[10,15)[30...")
+	remove(15, 35, "[10,15)[35...")
+	remove(20, 30, "[10,20)[30...")
+	remove(25, 35, "[10,20)[35...")
+	remove(30, 40, "[10,20)[40...")
+
+	// -----------------------------------
+	// TARGET: a pair of bounded intervals
+	// -----------------------------------
+	cs = byteset{10, 20, 30, 40}
+	remove(5, 5, "")
+	remove(10, 10, "")
+	remove(15, 15, "[10,15)")
+	remove(25, 25, "[10,20)")
+	remove(35, 35, "[10,20)[30,35)")
+	remove(45, 45, "[10,20)[30,40)")
+	remove(0, 45, "")
+	remove(10, 40, "")
+	remove(15, 35, "[10,15)[35,40)")
+	remove(20, 30, "[10,20)[30,40)")
+	remove(12, 14, "[10,12)[14,20)[30,40)")
+	remove(40, 45, "[10,20)[30,40)")
+}
+
+func Test_RemoveRandom(t *testing.T) {
+	for iter := 0; iter < 500; iter++ {
+		cs := random_byteset(rand.Intn(6))
+		l, h := byte(rand.Intn(256)), byte(rand.Intn(256))
+		tmp := slices.Clone(cs)
+		RemoveInterval(&tmp, l, h)
+
+		var iv byteset
+		InsertInterval(&iv, l, h)
+		if want := Difference(cs, iv); !slices.Equal(tmp, want) {
+			t.Fatalf("RemoveInterval(%v, %d, %d) = %v, want %v", cs, l, h, tmp, want)
+		}
+	}
+}
+
+func TestSet_Methods(t *testing.T) {
+	var s Set[float64]
+	s.Insert(0.5, 1.5)
+	s.Insert(3, 4)
+	s.Insert(10, 10)
+
+	if got, want := s.String(), "[0.5,1.5)[3,4)[10..."; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+	if s.Len() != 3 || s.IsEmpty() {
+		t.Errorf("Len() = %d, IsEmpty() = %v, want 3, false", s.Len(), s.IsEmpty())
+	}
+	if !s.Contains(1) || s.Contains(2) || !s.Contains(1e9) {
+		t.Errorf("unexpected containment in %v", s)
+	}
+	if got, want := s.Hull().String(), "[0.5..."; got != want {
+		t.Errorf("Hull() = %s, want %s", got, want)
+	}
+
+	c := s.Clone()
+	c.Remove(1, 3.5)
+	c.Join(0, 1e9)
+	if got, want := c.String(), "[0.5..."; got != want {
+		t.Errorf("Remove+Join = %s, want %s", got, want)
+	}
+	if c.Equal(s) || !s.Equal(s.Clone()) {
+		t.Errorf("Equal() mismatch")
+	}
+
+	o := Set[float64]{1, 3.5}
+	if got, want := s.Union(o).String(), "[0.5,4)[10..."; got != want {
+		t.Errorf("Union() = %s, want %s", got, want)
+	}
+	if got, want := s.Intersect(o).String(), "[1,1.5)[3,3.5)"; got != want {
+		t.Errorf("Intersect() = %s, want %s", got, want)
+	}
+	if got, want := s.Difference(o).String(), "[0.5,1)[3.5,4)[10..."; got != want {
+		t.Errorf("Difference() = %s, want %s", got, want)
+	}
+	if got, want := s.SymmetricDifference(o).String(), "[0.5,1)[1.5,3)[3.5,4)[10..."; got != want {
+		t.Errorf("SymmetricDifference() = %s, want %s", got, want)
+	}
+
+	n := 0
+	s.Enumerate(func(l, h float64) { n++ })
+	if n != 3 {
+		t.Errorf("Enumerate() visited %d intervals, want 3", n)
+	}
+}
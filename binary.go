@@ -0,0 +1,369 @@
+package ics
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"unsafe"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ErrCorrupted is returned when binary data can not be decoded into a set.
+var ErrCorrupted = errors.New("ics: corrupted binary data")
+
+const binary_version = 1
+
+// MarshalBinary implements encoding.BinaryMarshaler for sets of integer and
+// floating point types. Named element types, such as time.Duration, are
+// encoded the same way as their underlying types.
+//
+// The encoding consists of a header with the format version, the element type
+// tag and a flag that indicates an open-ended set, followed by the number of
+// boundaries and the boundaries themselves. The first boundary is written as
+// a zig-zag encoded varint for signed types and as a uvarint otherwise. The
+// others are written as plain uvarint deltas from their predecessors, which
+// need no zig-zag encoding: the boundaries are strictly increasing, so the
+// deltas are always positive. Floating point values are mapped to unsigned
+// integers that preserve their order, NaN values are not supported.
+func (s Set[T]) MarshalBinary() ([]byte, error) {
+	return marshal_binary(s)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The decoded
+// boundaries must be strictly increasing, otherwise an error wrapping
+// ErrCorrupted is returned.
+func (s *Set[T]) UnmarshalBinary(data []byte) error {
+	return unmarshal_binary(s, data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. See Set.MarshalBinary
+// for the description of the encoding.
+func (s RuneSet) MarshalBinary() ([]byte, error) {
+	return marshal_binary(s)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *RuneSet) UnmarshalBinary(data []byte) error {
+	var r RuneSet
+	if err := unmarshal_binary(&r, data); err != nil {
+		return err
+	}
+	if err := r.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	*s = r
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. See Set.MarshalBinary
+// for the description of the encoding.
+func (s AsciiSet) MarshalBinary() ([]byte, error) {
+	return marshal_binary(s)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *AsciiSet) UnmarshalBinary(data []byte) error {
+	var r AsciiSet
+	if err := unmarshal_binary(&r, data); err != nil {
+		return err
+	}
+	if err := r.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	*s = r
+	return nil
+}
+
+// float_key maps f to an integer with the same order among all non-NaN values
+// of the given bit size.
+func float_key(f float64, bits int) uint64 {
+	if bits == 32 {
+		k := uint64(math.Float32bits(float32(f)))
+		if k>>31 != 0 {
+			return ^k & math.MaxUint32
+		}
+		return k | 1<<31
+	}
+	k := math.Float64bits(f)
+	if k>>63 != 0 {
+		return ^k
+	}
+	return k | 1<<63
+}
+
+func float_from_key(k uint64, bits int) (float64, bool) {
+	var f float64
+	if bits == 32 {
+		if k > math.MaxUint32 {
+			return 0, false
+		}
+		if k>>31 != 0 {
+			k &^= 1 << 31
+		} else {
+			k = ^k & math.MaxUint32
+		}
+		f = float64(math.Float32frombits(uint32(k)))
+	} else {
+		if k>>63 != 0 {
+			k &^= 1 << 63
+		} else {
+			k = ^k
+		}
+		f = math.Float64frombits(k)
+	}
+	return f, !math.IsNaN(f)
+}
+
+func marshal_binary[S ~[]T, T constraints.Ordered](s S) ([]byte, error) {
+	// named element types are encoded as their underlying types
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Int8:
+		return marshal_ints(1, view[int8]([]T(s)))
+	case reflect.Int16:
+		return marshal_ints(2, view[int16]([]T(s)))
+	case reflect.Int32:
+		return marshal_ints(3, view[int32]([]T(s)))
+	case reflect.Int64:
+		return marshal_ints(4, view[int64]([]T(s)))
+	case reflect.Int:
+		return marshal_ints(5, view[int]([]T(s)))
+	case reflect.Uint8:
+		return marshal_ints(6, view[uint8]([]T(s)))
+	case reflect.Uint16:
+		return marshal_ints(7, view[uint16]([]T(s)))
+	case reflect.Uint32:
+		return marshal_ints(8, view[uint32]([]T(s)))
+	case reflect.Uint64:
+		return marshal_ints(9, view[uint64]([]T(s)))
+	case reflect.Uint:
+		return marshal_ints(10, view[uint]([]T(s)))
+	case reflect.Uintptr:
+		return marshal_ints(11, view[uintptr]([]T(s)))
+	case reflect.Float32:
+		return marshal_floats(12, view[float32]([]T(s)), 32)
+	case reflect.Float64:
+		return marshal_floats(13, view[float64]([]T(s)), 64)
+	}
+	return nil, fmt.Errorf("ics: unsupported element type %T", *new(T))
+}
+
+func unmarshal_binary[S ~[]T, T constraints.Ordered](s *S, data []byte) error {
+	var r []T
+	var err error
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Int8:
+		r, err = view_result[T](unmarshal_ints[int8](1, data))
+	case reflect.Int16:
+		r, err = view_result[T](unmarshal_ints[int16](2, data))
+	case reflect.Int32:
+		r, err = view_result[T](unmarshal_ints[int32](3, data))
+	case reflect.Int64:
+		r, err = view_result[T](unmarshal_ints[int64](4, data))
+	case reflect.Int:
+		r, err = view_result[T](unmarshal_ints[int](5, data))
+	case reflect.Uint8:
+		r, err = view_result[T](unmarshal_ints[uint8](6, data))
+	case reflect.Uint16:
+		r, err = view_result[T](unmarshal_ints[uint16](7, data))
+	case reflect.Uint32:
+		r, err = view_result[T](unmarshal_ints[uint32](8, data))
+	case reflect.Uint64:
+		r, err = view_result[T](unmarshal_ints[uint64](9, data))
+	case reflect.Uint:
+		r, err = view_result[T](unmarshal_ints[uint](10, data))
+	case reflect.Uintptr:
+		r, err = view_result[T](unmarshal_ints[uintptr](11, data))
+	case reflect.Float32:
+		r, err = view_result[T](unmarshal_floats[float32](12, data, 32))
+	case reflect.Float64:
+		r, err = view_result[T](unmarshal_floats[float64](13, data, 64))
+	default:
+		return fmt.Errorf("ics: unsupported element type %T", *new(T))
+	}
+	if err != nil {
+		return err
+	}
+	*s = S(r)
+	return nil
+}
+
+// view reinterprets s as a slice of U. T and U must share the same underlying
+// type, so that named element types can be processed as predeclared ones.
+func view[U, T any](s []T) []U {
+	return unsafe.Slice((*U)(unsafe.Pointer(unsafe.SliceData(s))), len(s))
+}
+
+// view_result is view for the results of the decoding functions.
+func view_result[T, U any](s []U, err error) ([]T, error) {
+	return view[T](s), err
+}
+
+func marshal_ints[T constraints.Integer](tag byte, s []T) ([]byte, error) {
+	signed := ^T(0) < 0
+	b := append_binary_header(tag, len(s))
+	for i, v := range s {
+		if i == 0 {
+			b = append_first_key(b, uint64(v), signed)
+			continue
+		}
+		if v <= s[i-1] {
+			return nil, fmt.Errorf("ics: set is not sorted at index %d", i)
+		}
+		b = binary.AppendUvarint(b, uint64(v)-uint64(s[i-1]))
+	}
+	return b, nil
+}
+
+func marshal_floats[T constraints.Float](tag byte, s []T, bits int) ([]byte, error) {
+	b := append_binary_header(tag, len(s))
+	var prev uint64
+	for i, v := range s {
+		if v != v {
+			return nil, fmt.Errorf("ics: unsupported value at index %d", i)
+		}
+		k := float_key(float64(v), bits)
+		if i == 0 {
+			b = append_first_key(b, k, false)
+		} else if v <= s[i-1] {
+			return nil, fmt.Errorf("ics: set is not sorted at index %d", i)
+		} else {
+			b = binary.AppendUvarint(b, k-prev)
+		}
+		prev = k
+	}
+	return b, nil
+}
+
+func unmarshal_ints[T constraints.Integer](tag byte, data []byte) ([]T, error) {
+	n, data, err := read_binary_header(tag, data)
+	if err != nil {
+		return nil, err
+	}
+	signed := ^T(0) < 0
+	r := make([]T, n)
+	var k uint64
+	for i := range r {
+		if k, data, err = read_key(data, i, k, signed); err != nil {
+			return nil, err
+		}
+		v := T(k)
+		if uint64(v) != k {
+			return nil, binary_corrupted(fmt.Sprintf("value out of range at index %d", i))
+		}
+		if i > 0 && v <= r[i-1] {
+			return nil, binary_corrupted(fmt.Sprintf("unsorted value at index %d", i))
+		}
+		r[i] = v
+	}
+	if len(data) > 0 {
+		return nil, binary_corrupted("unexpected trailing data")
+	}
+	return r, nil
+}
+
+func unmarshal_floats[T constraints.Float](tag byte, data []byte, bits int) ([]T, error) {
+	n, data, err := read_binary_header(tag, data)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]T, n)
+	var k uint64
+	for i := range r {
+		if k, data, err = read_key(data, i, k, false); err != nil {
+			return nil, err
+		}
+		f, ok := float_from_key(k, bits)
+		if !ok {
+			return nil, binary_corrupted(fmt.Sprintf("value out of range at index %d", i))
+		}
+		v := T(f)
+		if i > 0 && v <= r[i-1] {
+			return nil, binary_corrupted(fmt.Sprintf("unsorted value at index %d", i))
+		}
+		r[i] = v
+	}
+	if len(data) > 0 {
+		return nil, binary_corrupted("unexpected trailing data")
+	}
+	return r, nil
+}
+
+func binary_corrupted(msg string) error {
+	return fmt.Errorf("%w: %s", ErrCorrupted, msg)
+}
+
+func append_binary_header(tag byte, n int) []byte {
+	flags := byte(n & 1)
+	b := make([]byte, 0, 3+binary.MaxVarintLen64+2*n)
+	b = append(b, binary_version, tag, flags)
+	return binary.AppendUvarint(b, uint64(n))
+}
+
+// append_first_key writes the key of the first boundary, zig-zag encoded for
+// signed types.
+func append_first_key(b []byte, k uint64, signed bool) []byte {
+	if signed {
+		return binary.AppendVarint(b, int64(k))
+	}
+	return binary.AppendUvarint(b, k)
+}
+
+// read_binary_header checks the header and returns the number of boundaries
+// along with the remaining data.
+func read_binary_header(tag byte, data []byte) (int, []byte, error) {
+	if len(data) < 3 {
+		return 0, nil, binary_corrupted("missing header")
+	}
+	if data[0] != binary_version {
+		return 0, nil, binary_corrupted(fmt.Sprintf("unsupported version %d", data[0]))
+	}
+	if data[1] != tag {
+		return 0, nil, binary_corrupted("element type mismatch")
+	}
+	flags := data[2]
+	data = data[3:]
+
+	n, w := read_uvarint(data)
+	if w <= 0 {
+		return 0, nil, binary_corrupted("invalid length")
+	}
+	data = data[w:]
+	if n > uint64(len(data)) {
+		// each boundary takes at least one byte
+		return 0, nil, binary_corrupted("invalid length")
+	}
+	if flags != byte(n&1) {
+		return 0, nil, binary_corrupted("invalid flags")
+	}
+	return int(n), data, nil
+}
+
+// read_key decodes the key of the i-th boundary, prev is the key of its
+// predecessor. The key of the first boundary is zig-zag decoded for signed
+// types, the others are decoded as deltas.
+func read_key(data []byte, i int, prev uint64, signed bool) (uint64, []byte, error) {
+	k, w := read_uvarint(data)
+	if w <= 0 {
+		return 0, nil, binary_corrupted("invalid value")
+	}
+	switch {
+	case i > 0:
+		k += prev
+	case signed:
+		k = uint64(int64(k>>1) ^ -int64(k&1))
+	}
+	return k, data[w:], nil
+}
+
+// read_uvarint is binary.Uvarint that also rejects overlong encodings, so that
+// every set has exactly one valid encoding.
+func read_uvarint(data []byte) (uint64, int) {
+	v, w := binary.Uvarint(data)
+	if w > 1 && data[w-1] == 0 {
+		return 0, -w
+	}
+	return v, w
+}
@@ -0,0 +1,151 @@
+package ics
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+	"unicode"
+
+	"golang.org/x/exp/constraints"
+)
+
+func test_binary_roundtrip[T constraints.Ordered](t *testing.T, sets ...Set[T]) {
+	t.Helper()
+	for _, s := range sets {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			t.Errorf("%v.MarshalBinary() failed: %v", s, err)
+			continue
+		}
+		var got Set[T]
+		if err := got.UnmarshalBinary(b); err != nil {
+			t.Errorf("UnmarshalBinary(%x) failed: %v", b, err)
+		} else if !got.Equal(s) {
+			t.Errorf("UnmarshalBinary(%x) = %v, want %v", b, got, s)
+		}
+	}
+}
+
+func TestSet_MarshalBinary(t *testing.T) {
+	test_binary_roundtrip(t, Set[int]{}, Set[int]{-5}, Set[int]{-5, 3, 7}, Set[int]{math.MinInt, math.MaxInt})
+	test_binary_roundtrip(t, Set[int8]{math.MinInt8, -1, 0, math.MaxInt8})
+	test_binary_roundtrip(t, Set[int64]{math.MinInt64, math.MaxInt64})
+	test_binary_roundtrip(t, Set[uint8]{0, 1, 255})
+	test_binary_roundtrip(t, Set[uint64]{0, math.MaxUint64})
+	test_binary_roundtrip(t, Set[uintptr]{1, 2})
+	test_binary_roundtrip(t, Set[float32]{float32(math.Inf(-1)), -1.5, 0, 1e-45, 3.25, math.MaxFloat32})
+	test_binary_roundtrip(t, Set[float64]{math.Inf(-1), -0.0, 0.5, math.Inf(1)})
+}
+
+func TestSet_MarshalBinary_Named(t *testing.T) {
+	s := Set[time.Duration]{-time.Second, time.Millisecond, time.Hour}
+	test_binary_roundtrip(t, Set[time.Duration]{}, s, Set[time.Duration]{time.Minute})
+
+	// named types share the encoding of their underlying types
+	got, _ := s.MarshalBinary()
+	want, _ := Set[int64]{int64(-time.Second), int64(time.Millisecond), int64(time.Hour)}.MarshalBinary()
+	if string(got) != string(want) {
+		t.Errorf("%v.MarshalBinary() = %x, want %x", s, got, want)
+	}
+}
+
+func TestSet_MarshalBinary_Errors(t *testing.T) {
+	if _, err := (Set[string]{"a"}).MarshalBinary(); err == nil {
+		t.Error("MarshalBinary succeeds for strings")
+	}
+	if _, err := (Set[float64]{math.NaN()}).MarshalBinary(); err == nil {
+		t.Error("MarshalBinary succeeds for NaN")
+	}
+	if _, err := (Set[int]{3, 1}).MarshalBinary(); err == nil {
+		t.Error("MarshalBinary succeeds for an unsorted set")
+	}
+}
+
+func TestSet_UnmarshalBinary_Corrupted(t *testing.T) {
+	valid, _ := Set[int16]{-3, 5, 9}.MarshalBinary()
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"version", append([]byte{2}, valid[1:]...)},
+		{"type", append([]byte{1, 3}, valid[2:]...)},
+		{"flags", append([]byte{1, 2, 0}, valid[3:]...)},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing", append(valid, 0)},
+		{"length", []byte{1, 2, 0, 100, 1}},
+		{"duplicate", []byte{1, 2, 0, 2, 4, 0}},
+		{"overflow", []byte{1, 2, 1, 1, 0x80, 0x80, 0x04}},
+		{"overlong", []byte{1, 2, 0, 1, 0x86, 0x00}},
+		{"wrap", []byte{1, 2, 0, 2, 2, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	}
+	for _, tt := range tests {
+		s := Set[int16]{1}
+		err := s.UnmarshalBinary(tt.data)
+		if !errors.Is(err, ErrCorrupted) {
+			t.Errorf("%s: UnmarshalBinary(%x) = %v, want ErrCorrupted", tt.name, tt.data, err)
+		}
+		if !s.Equal(Set[int16]{1}) {
+			t.Errorf("%s: UnmarshalBinary(%x) modified the set", tt.name, tt.data)
+		}
+	}
+}
+
+func TestRuneSet_MarshalBinary(t *testing.T) {
+	for _, s := range []RuneSet{{}, {0}, {'a', 'z' + 1}, FromRangeTable(unicode.L)} {
+		b, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) > 8+2*len(s) {
+			t.Errorf("%v.MarshalBinary() takes %d bytes", s, len(b))
+		}
+		var got RuneSet
+		if err := got.UnmarshalBinary(b); err != nil || !got.Equal(s) {
+			t.Errorf("UnmarshalBinary(%x) = %v, %v, want %v", b, got, err, s)
+		}
+	}
+
+	b, _ := Set[int32]{0x110000}.MarshalBinary()
+	if err := new(RuneSet).UnmarshalBinary(b); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("RuneSet.UnmarshalBinary(%x) = %v, want ErrCorrupted", b, err)
+	}
+}
+
+func TestAsciiSet_MarshalBinary(t *testing.T) {
+	s := AsciiSet{'0', '9' + 1, 'a'}
+	b, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got AsciiSet
+	if err := got.UnmarshalBinary(b); err != nil || !got.Equal(s) {
+		t.Errorf("UnmarshalBinary(%x) = %v, %v, want %v", b, got, err, s)
+	}
+
+	b, _ = Set[byte]{0x80}.MarshalBinary()
+	if err := new(AsciiSet).UnmarshalBinary(b); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("AsciiSet.UnmarshalBinary(%x) = %v, want ErrCorrupted", b, err)
+	}
+}
+
+func FuzzSet_UnmarshalBinary(f *testing.F) {
+	valid, _ := Set[int32]{-7, 0, 100, 1 << 20, 1 << 30}.MarshalBinary()
+	f.Add(valid)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var s Set[int32]
+		if s.UnmarshalBinary(data) != nil {
+			return
+		}
+		for i := 1; i < len(s); i++ {
+			if s[i] <= s[i-1] {
+				t.Fatalf("UnmarshalBinary(%x) = %v is not sorted", data, s)
+			}
+		}
+		b, err := s.MarshalBinary()
+		if err != nil || string(b) != string(data) {
+			t.Fatalf("UnmarshalBinary(%x) = %v does not round-trip: %x, %v", data, s, b, err)
+		}
+	})
+}
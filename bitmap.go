@@ -0,0 +1,244 @@
+package ics
+
+import "math/bits"
+
+// AsciiBitmap is a compiled form of AsciiSet for fast containment tests and
+// byte scanning. Each bit of the bitmap corresponds to one ASCII character.
+// Bytes in the range [0x80..0xFF] are never contained in the bitmap, so it can
+// be used directly on UTF-8 encoded text.
+type AsciiBitmap [2]uint64
+
+// Bitmap compiles s into a bitmap.
+func (s AsciiSet) Bitmap() AsciiBitmap {
+	m := AsciiBitmap{}
+	s.EnumerateRanges(func(cmin, cmax byte) {
+		m.set_range(int(cmin), int(cmax))
+	})
+	return m
+}
+
+// set_range sets the bits of the characters [cmin..cmax], one 64-bit word at a
+// time.
+func (m *AsciiBitmap) set_range(cmin, cmax int) {
+	for c := cmin; c <= cmax; {
+		n := min(cmax, c|63) - c + 1
+		m[c>>6] |= (uint64(1)<<n - 1) << (c & 63)
+		c += n
+	}
+}
+
+// Contains indicates if c is contained within m.
+func (m AsciiBitmap) Contains(c byte) bool {
+	return m.bit(c) != 0
+}
+
+// bit returns 1 if c is contained within m, 0 otherwise.
+func (m *AsciiBitmap) bit(c byte) uint64 {
+	return (m[(c>>6)&1] >> (c & 63)) & 1 & uint64(^c>>7)
+}
+
+// IndexAny returns the index of the first byte of b that is contained in m,
+// or -1 if there is none.
+func (m AsciiBitmap) IndexAny(b []byte) int {
+	return bitmap_index(&m, b, 0)
+}
+
+// IndexAnyString returns the index of the first byte of s that is contained in
+// m, or -1 if there is none.
+func (m AsciiBitmap) IndexAnyString(s string) int {
+	return bitmap_index(&m, s, 0)
+}
+
+// IndexNot returns the index of the first byte of b that is not contained in
+// m, or -1 if there is none.
+func (m AsciiBitmap) IndexNot(b []byte) int {
+	return bitmap_index(&m, b, 1)
+}
+
+// IndexNotString returns the index of the first byte of s that is not
+// contained in m, or -1 if there is none.
+func (m AsciiBitmap) IndexNotString(s string) int {
+	return bitmap_index(&m, s, 1)
+}
+
+// Span returns the length of the leading run of bytes of b that are contained
+// in m.
+func (m AsciiBitmap) Span(b []byte) int {
+	if i := bitmap_index(&m, b, 1); i >= 0 {
+		return i
+	}
+	return len(b)
+}
+
+// SpanString returns the length of the leading run of bytes of s that are
+// contained in m.
+func (m AsciiBitmap) SpanString(s string) int {
+	if i := bitmap_index(&m, s, 1); i >= 0 {
+		return i
+	}
+	return len(s)
+}
+
+// TrimLeft returns a subslice of b with all leading bytes contained in m
+// removed.
+func (m AsciiBitmap) TrimLeft(b []byte) []byte {
+	return b[m.Span(b):]
+}
+
+// TrimLeftString returns a substring of s with all leading bytes contained in
+// m removed.
+func (m AsciiBitmap) TrimLeftString(s string) string {
+	return s[m.SpanString(s):]
+}
+
+// TrimRight returns a subslice of b with all trailing bytes contained in m
+// removed.
+func (m AsciiBitmap) TrimRight(b []byte) []byte {
+	return b[:bitmap_last_index(&m, b, 1)+1]
+}
+
+// TrimRightString returns a substring of s with all trailing bytes contained
+// in m removed.
+func (m AsciiBitmap) TrimRightString(s string) string {
+	return s[:bitmap_last_index(&m, s, 1)+1]
+}
+
+// bitmap_index returns the index of the first byte of s that is contained in
+// m (flip = 0) or that is not contained in m (flip = 1).
+//
+// Inputs of at least swar_min_len bytes are scanned eight bytes at a time,
+// see bitmap_swar; the remaining tail and bitmaps with too many ranges are
+// scanned with bitmap_index_bytes.
+func bitmap_index[S []byte | string](m *AsciiBitmap, s S, flip uint64) int {
+	i := 0
+	if len(s) >= swar_min_len {
+		if r, ok := m.swar(); ok {
+			not := -flip & swar_high
+			for ; i+8 <= len(s); i += 8 {
+				w := load64(s, i)
+				if flip == 0 && w&swar_high == swar_high {
+					// no ASCII bytes in this word
+					continue
+				}
+				if hits := r.members(w) ^ not; hits != 0 {
+					return i + bits.TrailingZeros64(hits)>>3
+				}
+			}
+		}
+	}
+	if j := bitmap_index_bytes(m, s[i:], flip); j >= 0 {
+		return i + j
+	}
+	return -1
+}
+
+// bitmap_last_index is similar to bitmap_index, but it returns the index of
+// the last matching byte.
+func bitmap_last_index[S []byte | string](m *AsciiBitmap, s S, flip uint64) int {
+	j := len(s)
+	if len(s) >= swar_min_len {
+		if r, ok := m.swar(); ok {
+			not := -flip & swar_high
+			for ; j >= 8; j -= 8 {
+				w := load64(s, j-8)
+				if flip == 0 && w&swar_high == swar_high {
+					continue
+				}
+				if hits := r.members(w) ^ not; hits != 0 {
+					return j - 8 + (63-bits.LeadingZeros64(hits))>>3
+				}
+			}
+		}
+	}
+	return bitmap_last_index_bytes(m, s[:j], flip)
+}
+
+// bitmap_index_bytes is bitmap_index that tests the bytes one at a time, each
+// test is a branch-free bitmap lookup.
+func bitmap_index_bytes[S []byte | string](m *AsciiBitmap, s S, flip uint64) int {
+	for i := 0; i < len(s); i++ {
+		if m.bit(s[i]) != flip {
+			return i
+		}
+	}
+	return -1
+}
+
+// bitmap_last_index_bytes is bitmap_last_index that tests the bytes one at a
+// time.
+func bitmap_last_index_bytes[S []byte | string](m *AsciiBitmap, s S, flip uint64) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if m.bit(s[i]) != flip {
+			return i
+		}
+	}
+	return -1
+}
+
+const (
+	// swar_max_ranges is the largest number of ranges in a bitmap that is
+	// scanned eight bytes at a time.
+	swar_max_ranges = 8
+	// swar_min_len is the shortest input that is worth the setup of bitmap_swar.
+	swar_min_len = 16
+
+	swar_ones = 0x0101010101010101
+	swar_high = 0x8080808080808080
+)
+
+// bitmap_swar is the form of AsciiBitmap that tests all eight bytes of a word
+// at once (SIMD within a register). With the high bits of the bytes cleared,
+// adding ge[k] to the word carries into the high bit of every byte that is
+// >= the lower bound of the k-th range, and adding gt[k] does the same for
+// every byte that is above its upper bound. The bytes never carry into their
+// neighbours.
+type bitmap_swar struct {
+	n      int
+	ge, gt [swar_max_ranges]uint64
+}
+
+// swar returns the SWAR form of m, ok is false if m consists of more than
+// swar_max_ranges ranges.
+func (m *AsciiBitmap) swar() (r bitmap_swar, ok bool) {
+	for c := m.next(0, 1); c < 0x80; c = m.next(c, 1) {
+		if r.n == swar_max_ranges {
+			return r, false
+		}
+		e := m.next(c, 0)
+		r.ge[r.n] = swar_ones * uint64(0x80-c)
+		r.gt[r.n] = swar_ones * uint64(0x80-e)
+		r.n++
+		c = e
+	}
+	return r, true
+}
+
+// next returns the first character at or after c whose bit equals v, or 0x80
+// if there is none.
+func (m *AsciiBitmap) next(c int, v uint64) int {
+	for c < 0x80 {
+		if w := (m[c>>6] ^ (v - 1)) >> (c & 63); w != 0 {
+			return c + bits.TrailingZeros64(w)
+		}
+		c = (c | 63) + 1
+	}
+	return 0x80
+}
+
+// members returns the high bits of the bytes of w that are contained in the
+// bitmap.
+func (r *bitmap_swar) members(w uint64) uint64 {
+	x := w &^ swar_high
+	in := uint64(0)
+	for k := 0; k < r.n; k++ {
+		in |= (x + r.ge[k]) &^ (x + r.gt[k])
+	}
+	return in &^ w & swar_high
+}
+
+// load64 returns the eight bytes of s at i as a little-endian word.
+func load64[S []byte | string](s S, i int) uint64 {
+	s = s[i : i+8]
+	return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+		uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
@@ -0,0 +1,159 @@
+package ics
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func random_asciiset() AsciiSet {
+	b := AsciiSetBuilder{}
+	for n := rand.Intn(6); n > 0; n-- {
+		lo := byte(rand.Intn(0x80))
+		hi := lo + byte(rand.Intn(0x80-int(lo)))
+		b.AddRange(lo, hi)
+	}
+	return b.Build()
+}
+
+func random_text(s AsciiSet, n int) []byte {
+	r := make([]byte, n)
+	for i := range r {
+		switch c := byte(rand.Intn(0x100)); {
+		case rand.Intn(2) == 0:
+			r[i] = c
+		case rand.Intn(4) == 0:
+			// runs of matching bytes are more interesting
+			r[i] = 0xff
+		default:
+			for !s.Contains(c) && len(s) > 0 {
+				c = byte(rand.Intn(0x80))
+			}
+			r[i] = c
+		}
+	}
+	return r
+}
+
+func TestAsciiBitmap(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		s := random_asciiset()
+		if i%4 == 0 {
+			// more ranges than the word-at-a-time scan handles
+			s = Union(s, AsciiSet("02468@BDFHJ"))
+		}
+		m := s.Bitmap()
+		for c := 0; c < 0x100; c++ {
+			if m.Contains(byte(c)) != (c < 0x80 && s.Contains(byte(c))) {
+				t.Fatalf("%v.Bitmap().Contains(%#x) is wrong", s, c)
+			}
+		}
+
+		in := func(r rune) bool {
+			return r < 0x80 && s.Contains(byte(r))
+		}
+		b := random_text(s, rand.Intn(80))
+		str := string(b)
+
+		index := func(in func(rune) bool) int {
+			for i, c := range b {
+				if in(rune(c)) {
+					return i
+				}
+			}
+			return -1
+		}
+		not_in := func(r rune) bool {
+			return !in(r)
+		}
+		if got, want := m.IndexAny(b), index(in); got != want || m.IndexAnyString(str) != want {
+			t.Errorf("%v.IndexAny(%q) = %d, want %d", s, b, got, want)
+		}
+		if got, want := m.IndexNot(b), index(not_in); got != want || m.IndexNotString(str) != want {
+			t.Errorf("%v.IndexNot(%q) = %d, want %d", s, b, got, want)
+		}
+		span := len(b)
+		if i := index(not_in); i >= 0 {
+			span = i
+		}
+		if got := m.Span(b); got != span || m.SpanString(str) != span {
+			t.Errorf("%v.Span(%q) = %d, want %d", s, b, got, span)
+		}
+
+		// bytes.TrimFunc works with runes, so compare it with byte-wise trimming
+		trim_right := len(b)
+		for trim_right > 0 && in(rune(b[trim_right-1])) {
+			trim_right--
+		}
+		if got := m.TrimLeft(b); !bytes.Equal(got, b[span:]) || m.TrimLeftString(str) != str[span:] {
+			t.Errorf("%v.TrimLeft(%q) = %q, want %q", s, b, got, b[span:])
+		}
+		if got := m.TrimRight(b); !bytes.Equal(got, b[:trim_right]) || m.TrimRightString(str) != str[:trim_right] {
+			t.Errorf("%v.TrimRight(%q) = %q, want %q", s, b, got, b[:trim_right])
+		}
+	}
+}
+
+func TestAsciiBitmap_Words(t *testing.T) {
+	m := AsciiSet{'a', 'z' + 1}.Bitmap()
+	tests := []struct {
+		s          string
+		index      int
+		last_index int
+	}{
+		{strings.Repeat("ΨΨΨΨ", 4), -1, -1},
+		{strings.Repeat("ΨΨΨΨ", 4) + "a", 32, 32},
+		{"x" + strings.Repeat("ΨΨΨΨ", 4), 0, 0},
+		{strings.Repeat("Ψ", 9) + "b" + strings.Repeat("Ψ", 9), 18, 18},
+		{"0123456789abcdefghijklmnopqrstuv", 10, 31},
+	}
+	for _, tt := range tests {
+		if got := m.IndexAnyString(tt.s); got != tt.index {
+			t.Errorf("IndexAnyString(%q) = %d, want %d", tt.s, got, tt.index)
+		}
+		if got := bitmap_last_index(&m, tt.s, 0); got != tt.last_index {
+			t.Errorf("bitmap_last_index(%q) = %d, want %d", tt.s, got, tt.last_index)
+		}
+	}
+}
+
+func BenchmarkAsciiBitmap_Span(b *testing.B) {
+	ident := AsciiSet{'0', '9' + 1, 'A', 'Z' + 1, '_', '_' + 1, 'a', 'z' + 1}
+	text := strings.Repeat("some_identifier_42", 8) + " "
+	b.Run("AsciiSet", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			n := 0
+			for n < len(text) && ident.Contains(text[n]) {
+				n++
+			}
+		}
+	})
+	b.Run("AsciiBitmap", func(b *testing.B) {
+		m := ident.Bitmap()
+		for i := 0; i < b.N; i++ {
+			m.SpanString(text)
+		}
+	})
+	b.Run("AsciiBitmap/bytes", func(b *testing.B) {
+		m := ident.Bitmap()
+		for i := 0; i < b.N; i++ {
+			bitmap_index_bytes(&m, text, 1)
+		}
+	})
+}
+
+func BenchmarkAsciiBitmap_IndexAny(b *testing.B) {
+	digits := AsciiSet{'0', '9' + 1}.Bitmap()
+	text := strings.Repeat("Ψ some text without any numbers,", 8) + "0"
+	b.Run("bytes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bitmap_index_bytes(&digits, text, 0)
+		}
+	})
+	b.Run("words", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			digits.IndexAnyString(text)
+		}
+	})
+}
@@ -90,3 +90,48 @@ func InsertInterval[S ~[]T, T constraints.Ordered](s *S, l, h T) {
 		(*s)[li+1] = h
 	}
 }
+
+// RemoveInterval carves an interval out of s.
+//
+//   - if l < h, a bounded interval [l,h) is removed
+//   - if l >= h, a half-open interval [l,... is removed instead
+func RemoveInterval[S ~[]T, T constraints.Ordered](s *S, l, h T) {
+	if len(*s) == 0 {
+		return
+	}
+
+	// elements below li are less than l, the state at l is determined by the
+	// parity of li: odd means that l is contained within an interval that has
+	// to be closed at l
+	li, _ := search(*s, l)
+
+	if h <= l {
+		// removing open-ended interval [l...
+		if li&1 == 1 {
+			*s = append((*s)[:li], l)
+		} else {
+			*s = (*s)[:li]
+		}
+		return
+	}
+
+	// elements below hi are less than or equal to h, odd hi means that h is
+	// contained within an interval that has to be reopened at h
+	hi, h_be := search((*s)[li:], h)
+	hi += li
+	if h_be {
+		hi++
+	}
+
+	var v [2]T
+	m := 0
+	if li&1 == 1 {
+		v[m] = l
+		m++
+	}
+	if hi&1 == 1 {
+		v[m] = h
+		m++
+	}
+	*s = slices.Replace(*s, li, hi, v[:m]...)
+}
@@ -0,0 +1,183 @@
+package ics
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// Builder accumulates intervals in arbitrary order, then produces a flattened
+// set with a single sort-and-sweep pass.
+//
+// Building a large set this way takes O(n log n) time, while inserting the
+// same intervals one-by-one with InsertInterval is quadratic.
+type Builder[T constraints.Ordered] struct {
+	intervals [][2]T
+	open      T
+	has_open  bool
+}
+
+// Add accumulates an interval.
+//
+//   - if l < h, a bounded interval [l,h) is added
+//   - if l >= h, a half-open interval [l,... is added instead
+func (b *Builder[T]) Add(l, h T) {
+	if h <= l {
+		if !b.has_open || l < b.open {
+			b.open = l
+			b.has_open = true
+		}
+		return
+	}
+	b.intervals = append(b.intervals, [2]T{l, h})
+}
+
+// Reset discards all accumulated intervals.
+func (b *Builder[T]) Reset() {
+	b.intervals = b.intervals[:0]
+	b.has_open = false
+}
+
+// Build returns a flattened set that contains all accumulated intervals. The
+// builder remains usable after this call.
+func (b *Builder[T]) Build() Set[T] {
+	slices.SortFunc(b.intervals, func(x, y [2]T) bool {
+		return x[0] < y[0]
+	})
+
+	s := make(Set[T], 0, 2*len(b.intervals)+1)
+	for _, v := range b.intervals {
+		if b.has_open && v[0] >= b.open {
+			// all remaining intervals are swallowed by the open-ended one
+			break
+		}
+		n := len(s)
+		if n > 0 && v[0] <= s[n-1] {
+			if v[1] > s[n-1] {
+				s[n-1] = v[1]
+			}
+		} else {
+			s = append(s, v[0], v[1])
+		}
+	}
+
+	if b.has_open {
+		// only the last interval may reach the open-ended one
+		n := len(s)
+		if n > 0 && s[n-1] >= b.open {
+			s = s[:n-1]
+		} else {
+			s = append(s, b.open)
+		}
+	}
+	return s
+}
+
+// domain_add_range adds a valid inclusive [lo,hi] range to b.
+func domain_add_range[T constraints.Integer](d Domain[T], b *Builder[T], lo, hi T) {
+	if hi == d.Max {
+		b.Add(lo, lo)
+	} else {
+		b.Add(lo, hi+1)
+	}
+}
+
+func domain_try_add_range[T constraints.Integer](d Domain[T], b *Builder[T], lo, hi T) error {
+	if err := domain_check_range(d, lo, hi); err != nil {
+		return err
+	}
+	domain_add_range(d, b, lo, hi)
+	return nil
+}
+
+// RuneSetBuilder is a Builder for RuneSet that accepts inclusive ranges of
+// unicode codepoints.
+type RuneSetBuilder struct {
+	b Builder[rune]
+}
+
+// Add accumulates r.
+func (b *RuneSetBuilder) Add(r rune) {
+	if r < 0 || r > utf8.MaxRune {
+		panic("unsupported rune value")
+	}
+	domain_add_range(RuneDomain, &b.b, r, r)
+}
+
+// TryAdd is Add that returns an error wrapping ErrOutOfDomain instead of
+// panicking.
+func (b *RuneSetBuilder) TryAdd(r rune) error {
+	return domain_try_add_range(RuneDomain, &b.b, r, r)
+}
+
+// AddRange accumulates an inclusive [rmin,rmax] range of unicode codepoints.
+func (b *RuneSetBuilder) AddRange(rmin, rmax rune) {
+	if rmax < rmin {
+		panic("invalid rune range")
+	}
+	if rmin < 0 || rmax > utf8.MaxRune {
+		panic("unsupported rune value")
+	}
+	domain_add_range(RuneDomain, &b.b, rmin, rmax)
+}
+
+// TryAddRange is AddRange that returns an error wrapping ErrInvalidRange or
+// ErrOutOfDomain instead of panicking.
+func (b *RuneSetBuilder) TryAddRange(rmin, rmax rune) error {
+	return domain_try_add_range(RuneDomain, &b.b, rmin, rmax)
+}
+
+// Reset discards all accumulated ranges.
+func (b *RuneSetBuilder) Reset() {
+	b.b.Reset()
+}
+
+// Build returns a RuneSet that contains all accumulated ranges.
+func (b *RuneSetBuilder) Build() RuneSet {
+	return RuneSet(b.b.Build())
+}
+
+// AsciiSetBuilder is a Builder for AsciiSet that accepts inclusive ranges of
+// ascii characters.
+type AsciiSetBuilder struct {
+	b Builder[byte]
+}
+
+// Add accumulates c.
+func (b *AsciiSetBuilder) Add(c byte) {
+	if c > 0x7f {
+		panic("invalid ascii value")
+	}
+	domain_add_range(AsciiDomain, &b.b, c, c)
+}
+
+// TryAdd is Add that returns an error wrapping ErrOutOfDomain instead of
+// panicking.
+func (b *AsciiSetBuilder) TryAdd(c byte) error {
+	return domain_try_add_range(AsciiDomain, &b.b, c, c)
+}
+
+// AddRange accumulates an inclusive [cmin,cmax] range of ascii characters.
+func (b *AsciiSetBuilder) AddRange(cmin, cmax byte) {
+	if cmax < cmin || cmax > 0x7f {
+		panic("invalid ascii range")
+	}
+	domain_add_range(AsciiDomain, &b.b, cmin, cmax)
+}
+
+// TryAddRange is AddRange that returns an error wrapping ErrInvalidRange or
+// ErrOutOfDomain instead of panicking.
+func (b *AsciiSetBuilder) TryAddRange(cmin, cmax byte) error {
+	return domain_try_add_range(AsciiDomain, &b.b, cmin, cmax)
+}
+
+// Reset discards all accumulated ranges.
+func (b *AsciiSetBuilder) Reset() {
+	b.b.Reset()
+}
+
+// Build returns an AsciiSet that contains all accumulated ranges.
+func (b *AsciiSetBuilder) Build() AsciiSet {
+	return AsciiSet(b.b.Build())
+}
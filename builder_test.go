@@ -0,0 +1,89 @@
+package ics
+
+import (
+	"math/rand"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestBuilder(t *testing.T) {
+	for iter := 0; iter < 1000; iter++ {
+		var b Builder[byte]
+		var want byteset
+		for i, n := 0, rand.Intn(8); i < n; i++ {
+			l, h := byte(rand.Intn(256)), byte(rand.Intn(256))
+			if h > l && rand.Intn(8) == 0 {
+				h = l
+			}
+			b.Add(l, h)
+			InsertInterval(&want, l, h)
+		}
+		if got := b.Build(); !slices.Equal(got, Set[byte](want)) {
+			t.Fatalf("Builder produced %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRuneSetBuilder(t *testing.T) {
+	var b RuneSetBuilder
+	b.AddRange('a', 'z')
+	b.Add('_')
+	b.AddRange('0', '9')
+	b.AddRange('A', 'Z')
+	b.AddRange(0x10000, 0x10ffff)
+	b.AddRange('m', 'p')
+
+	want := RuneSet{'0', '9' + 1, 'A', 'Z' + 1, '_', '_' + 1, 'a', 'z' + 1, 0x10000}
+	if got := b.Build(); !slices.Equal(got, want) {
+		t.Errorf("RuneSetBuilder produced %v, want %v", got, want)
+	}
+
+	b.Reset()
+	if got := b.Build(); len(got) != 0 {
+		t.Errorf("RuneSetBuilder after Reset produced %v, want empty", got)
+	}
+}
+
+func TestAsciiSetBuilder(t *testing.T) {
+	var b AsciiSetBuilder
+	b.AddRange('x', 0x7f)
+	b.AddRange('a', 'c')
+	b.Add('d')
+
+	want := AsciiSet{'a', 'e', 'x'}
+	if got := b.Build(); !slices.Equal(got, want) {
+		t.Errorf("AsciiSetBuilder produced %v, want %v", got, want)
+	}
+}
+
+func random_intervals(n int) [][2]int {
+	r := rand.New(rand.NewSource(1))
+	v := make([][2]int, n)
+	for i := range v {
+		l := r.Intn(1 << 24)
+		v[i] = [2]int{l, l + 1 + r.Intn(64)}
+	}
+	return v
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	intervals := random_intervals(20000)
+	for i := 0; i < b.N; i++ {
+		var bld Builder[int]
+		for _, v := range intervals {
+			bld.Add(v[0], v[1])
+		}
+		bld.Build()
+	}
+}
+
+func BenchmarkInsertInterval(b *testing.B) {
+	intervals := random_intervals(20000)
+	for i := 0; i < b.N; i++ {
+		var s Set[int]
+		for _, v := range intervals {
+			InsertInterval(&s, v[0], v[1])
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package ics
+
+import (
+	"sort"
+	"sync"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+)
+
+// FoldCase returns the closure of s under simple case folding: the result
+// contains every rune whose unicode.SimpleFold orbit intersects s, which is
+// the set of runes that match s case-insensitively.
+func (s RuneSet) FoldCase() RuneSet {
+	fold_table_once.Do(load_fold_table)
+	b := RuneSetBuilder{}
+	i := 0
+	s.EnumerateRanges(func(lo, hi rune) {
+		b.AddRange(lo, hi)
+		for i < len(fold_table) && fold_table[i].hi < lo {
+			i++
+		}
+		for j := i; j < len(fold_table) && fold_table[j].lo <= hi; j++ {
+			seg := &fold_table[j]
+			l, h := max(lo, seg.lo), min(hi, seg.hi)
+			if seg.pairs {
+				// extend to the partners at both ends
+				if (l-seg.lo)&1 != 0 {
+					l--
+				}
+				if (h-seg.lo)&1 == 0 {
+					h++
+				}
+				b.AddRange(l, h)
+				continue
+			}
+			for _, d := range seg.deltas {
+				b.AddRange(l+d, h+d)
+			}
+		}
+	})
+	return b.Build()
+}
+
+// FoldCaseSpecial is like FoldCase, but the runes covered by c, such as the
+// dotted and dotless i of unicode.TurkishCase, are folded with the mappings
+// of c instead of their simple folding orbits: each of them is equivalent to
+// its upper, lower and title case mappings in c.
+func (s RuneSet) FoldCaseSpecial(c unicode.SpecialCase) RuneSet {
+	cb := RuneSetBuilder{}
+	for _, cr := range c {
+		cb.AddRange(rune(cr.Lo), rune(cr.Hi))
+	}
+	covered := cb.Build()
+	r := Difference(Difference(s, covered).FoldCase(), covered)
+
+	// special case tables are small, so the covered runes and their mappings
+	// are linked one by one
+	links := map[rune][]rune{}
+	link := func(x, y rune) {
+		if x != y {
+			links[x] = append(links[x], y)
+			links[y] = append(links[y], x)
+		}
+	}
+	for _, cr := range c {
+		for x := rune(cr.Lo); x <= rune(cr.Hi); x++ {
+			link(x, c.ToUpper(x))
+			link(x, c.ToLower(x))
+			link(x, c.ToTitle(x))
+		}
+	}
+
+	seen := map[rune]bool{}
+	var visit func(x rune)
+	visit = func(x rune) {
+		if !seen[x] {
+			seen[x] = true
+			for _, y := range links[x] {
+				visit(y)
+			}
+		}
+	}
+	for x := range links {
+		if s.Contains(x) || r.Contains(x) {
+			visit(x)
+		}
+	}
+	b := RuneSetBuilder{}
+	for x := range seen {
+		b.Add(x)
+	}
+	linked := b.Build()
+
+	// uncovered runes reached through the links bring their own orbits
+	r = Union(r, Difference(Difference(linked, covered).FoldCase(), covered))
+	return Union(Union(r, linked), Intersect(s, covered))
+}
+
+// ToUpper returns the image of s under unicode.ToUpper.
+func (s RuneSet) ToUpper() RuneSet {
+	return case_image(s, unicode.UpperCase, unicode.CaseRanges)
+}
+
+// ToLower returns the image of s under unicode.ToLower.
+func (s RuneSet) ToLower() RuneSet {
+	return case_image(s, unicode.LowerCase, unicode.CaseRanges)
+}
+
+// ToTitle returns the image of s under unicode.ToTitle.
+func (s RuneSet) ToTitle() RuneSet {
+	return case_image(s, unicode.TitleCase, unicode.CaseRanges)
+}
+
+// ToUpperSpecial returns the image of s under c.ToUpper.
+func (s RuneSet) ToUpperSpecial(c unicode.SpecialCase) RuneSet {
+	return case_image(s, unicode.UpperCase, c, unicode.CaseRanges)
+}
+
+// ToLowerSpecial returns the image of s under c.ToLower.
+func (s RuneSet) ToLowerSpecial(c unicode.SpecialCase) RuneSet {
+	return case_image(s, unicode.LowerCase, c, unicode.CaseRanges)
+}
+
+// ToTitleSpecial returns the image of s under c.ToTitle.
+func (s RuneSet) ToTitleSpecial(c unicode.SpecialCase) RuneSet {
+	return case_image(s, unicode.TitleCase, c, unicode.CaseRanges)
+}
+
+// case_image maps s with the case tables, the first table that covers a rune
+// determines its mapping. Runes that are not covered map to themselves.
+func case_image(s RuneSet, _case int, tables ...[]unicode.CaseRange) RuneSet {
+	b := RuneSetBuilder{}
+	s.EnumerateRanges(func(lo, hi rune) {
+		case_map(&b, _case, tables, lo, hi)
+	})
+	return b.Build()
+}
+
+func case_map(b *RuneSetBuilder, _case int, tables [][]unicode.CaseRange, lo, hi rune) {
+	if len(tables) == 0 {
+		b.AddRange(lo, hi)
+		return
+	}
+	t := tables[0]
+	i := sort.Search(len(t), func(i int) bool {
+		return rune(t[i].Hi) >= lo
+	})
+	for ; i < len(t) && rune(t[i].Lo) <= hi; i++ {
+		cr := &t[i]
+		if rune(cr.Lo) > lo {
+			case_map(b, _case, tables[1:], lo, rune(cr.Lo)-1)
+			lo = rune(cr.Lo)
+		}
+		h := min(hi, rune(cr.Hi))
+		if d := cr.Delta[_case]; d > unicode.MaxRune {
+			// alternating upper and lower case letters, see unicode.UpperLower:
+			// the low bit of the offset within the range selects the case
+			base, bit := rune(cr.Lo), rune(_case&1)
+			last := base + ((h-base)&^1 | bit)
+			for r := base + ((lo-base)&^1 | bit); r <= last; r += 2 {
+				b.Add(r)
+			}
+		} else {
+			b.AddRange(lo+d, h+d)
+		}
+		if h == hi {
+			return
+		}
+		lo = h + 1
+	}
+	case_map(b, _case, tables[1:], lo, hi)
+}
+
+// fold_segment describes the simple folding orbits of the runes [lo,hi].
+// Either all of them have the same orbit shape, each rune r is equivalent to
+// r+d for all deltas, or the range consists of alternating upper and lower
+// case pairs, each pair being an orbit of its own.
+type fold_segment struct {
+	lo, hi rune
+	pairs  bool
+	deltas []rune
+}
+
+var (
+	fold_table_once sync.Once
+	fold_table      []fold_segment
+)
+
+// load_fold_table collects the orbits of the runes that have case mappings
+// and of the cased letters, which include all runes with non-trivial orbits,
+// and compresses them into segments.
+func load_fold_table() {
+	b := RuneSetBuilder{}
+	for _, cr := range unicode.CaseRanges {
+		b.AddRange(rune(cr.Lo), rune(cr.Hi))
+	}
+	cased := MergeRuneSets(b.Build(), FromRangeTable(unicode.Lu), FromRangeTable(unicode.Ll), FromRangeTable(unicode.Lt))
+
+	orbits := map[rune][]rune{}
+	cased.EnumerateRanges(func(lo, hi rune) {
+		for r := lo; r <= hi; r++ {
+			if _, ok := orbits[r]; ok {
+				continue
+			}
+			orbit := []rune{r}
+			for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+				orbit = append(orbit, f)
+			}
+			for _, m := range orbit {
+				deltas := []rune{}
+				for _, o := range orbit {
+					if o != m {
+						deltas = append(deltas, o-m)
+					}
+				}
+				slices.Sort(deltas)
+				orbits[m] = deltas
+			}
+		}
+	})
+
+	runes := make([]rune, 0, len(orbits))
+	for r, deltas := range orbits {
+		if len(deltas) > 0 {
+			runes = append(runes, r)
+		}
+	}
+	slices.Sort(runes)
+
+	is_pair := func(i int) bool {
+		return i+1 < len(runes) && runes[i+1] == runes[i]+1 &&
+			slices.Equal(orbits[runes[i]], []rune{1}) &&
+			slices.Equal(orbits[runes[i+1]], []rune{-1})
+	}
+	for i := 0; i < len(runes); {
+		lo := runes[i]
+		if is_pair(i) {
+			j := i + 2
+			for is_pair(j) && runes[j] == runes[j-1]+1 {
+				j += 2
+			}
+			fold_table = append(fold_table, fold_segment{lo: lo, hi: runes[j-1], pairs: true})
+			i = j
+			continue
+		}
+		deltas := orbits[lo]
+		j := i + 1
+		for j < len(runes) && runes[j] == runes[j-1]+1 && slices.Equal(orbits[runes[j]], deltas) {
+			j++
+		}
+		fold_table = append(fold_table, fold_segment{lo: lo, hi: runes[j-1], deltas: deltas})
+		i = j
+	}
+}
@@ -0,0 +1,143 @@
+package ics
+
+import (
+	"math/rand"
+	"testing"
+	"unicode"
+)
+
+// random_cased_runeset produces a set of ranges within the areas of cased
+// letters.
+func random_cased_runeset(n int) RuneSet {
+	pool := []rune{0, 'A', 0xb5, 0xdf, 0x100, 0x130, 0x17f, 0x1c4, 0x345, 0x390, 0x3c2, 0x400, 0x10a0, 0x1e00, 0x1e9b, 0x1f00, 0x2126, 0x2c00, 0xa640, 0xff21, 0x10400, 0x1e900}
+	b := RuneSetBuilder{}
+	for i := 0; i < n; i++ {
+		lo := pool[rand.Intn(len(pool))] + rune(rand.Intn(64))
+		b.AddRange(lo, lo+rune(rand.Intn(64)))
+	}
+	return b.Build()
+}
+
+// map_runes is the per-codepoint reference for the case mappings.
+func map_runes(s RuneSet, f func(r rune) []rune) RuneSet {
+	b := RuneSetBuilder{}
+	s.EnumerateRanges(func(lo, hi rune) {
+		for r := lo; r <= hi; r++ {
+			for _, m := range f(r) {
+				b.Add(m)
+			}
+		}
+	})
+	return b.Build()
+}
+
+func fold_orbit(r rune) []rune {
+	orbit := []rune{r}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		orbit = append(orbit, f)
+	}
+	return orbit
+}
+
+func TestRuneSet_FoldCase(t *testing.T) {
+	tests := []struct {
+		s    RuneSet
+		want RuneSet
+	}{
+		{RuneSet{}, RuneSet{}},
+		{RuneSet{'k', 'k' + 1}, RuneSet{'K', 'K' + 1, 'k', 'k' + 1, 0x212a, 0x212b}},
+		{RuneSet{'a', 'c' + 1}, RuneSet{'A', 'C' + 1, 'a', 'c' + 1}},
+		{RuneSet{0x101, 0x104}, RuneSet{0x100, 0x104}},
+		{RuneSet{0x10000}, RuneSet{0x10000}},
+	}
+	for _, tt := range tests {
+		if got := tt.s.FoldCase(); !got.Equal(tt.want) {
+			t.Errorf("%v.FoldCase() = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+
+	// every rune with a non-trivial orbit must be covered by the table
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		if unicode.SimpleFold(r) != r {
+			s := RuneSet{r, r + 1}
+			if got, want := s.FoldCase(), map_runes(s, fold_orbit); !got.Equal(want) {
+				t.Fatalf("%v.FoldCase() = %v, want %v", s, got, want)
+			}
+		}
+	}
+
+	for i := 0; i < 200; i++ {
+		s := random_cased_runeset(rand.Intn(6))
+		if got, want := s.FoldCase(), map_runes(s, fold_orbit); !got.Equal(want) {
+			t.Fatalf("%v.FoldCase() = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestRuneSet_ToUpper(t *testing.T) {
+	one := func(f func(rune) rune) func(rune) []rune {
+		return func(r rune) []rune { return []rune{f(r)} }
+	}
+	turkish := unicode.TurkishCase
+	for i := 0; i < 200; i++ {
+		s := random_cased_runeset(rand.Intn(6))
+		if i == 0 {
+			s = RuneSet{0}
+		}
+		tests := []struct {
+			name      string
+			got, want RuneSet
+		}{
+			{"ToUpper", s.ToUpper(), map_runes(s, one(unicode.ToUpper))},
+			{"ToLower", s.ToLower(), map_runes(s, one(unicode.ToLower))},
+			{"ToTitle", s.ToTitle(), map_runes(s, one(unicode.ToTitle))},
+			{"ToUpperSpecial", s.ToUpperSpecial(turkish), map_runes(s, one(turkish.ToUpper))},
+			{"ToLowerSpecial", s.ToLowerSpecial(turkish), map_runes(s, one(turkish.ToLower))},
+			{"ToTitleSpecial", s.ToTitleSpecial(turkish), map_runes(s, one(turkish.ToTitle))},
+		}
+		for _, tt := range tests {
+			if !tt.got.Equal(tt.want) {
+				t.Fatalf("%v.%s() = %v, want %v", s, tt.name, tt.got, tt.want)
+			}
+		}
+	}
+}
+
+func TestRuneSet_FoldCaseSpecial(t *testing.T) {
+	tests := []struct {
+		s    RuneSet
+		want RuneSet
+	}{
+		{RuneSet{'i', 'i' + 1}, RuneSet{'i', 'i' + 1, 0x130, 0x131}},
+		{RuneSet{'I', 'I' + 1}, RuneSet{'I', 'I' + 1, 0x131, 0x132}},
+		{RuneSet{0x130, 0x132}, RuneSet{'I', 'I' + 1, 'i', 'i' + 1, 0x130, 0x132}},
+		{RuneSet{'h', 'k' + 1}, RuneSet{'H', 'H' + 1, 'J', 'K' + 1, 'h', 'k' + 1, 0x130, 0x131, 0x212a, 0x212b}},
+	}
+	for _, tt := range tests {
+		if got := tt.s.FoldCaseSpecial(unicode.TurkishCase); !got.Equal(tt.want) {
+			t.Errorf("%v.FoldCaseSpecial(TurkishCase) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+
+	// without special mappings, the result is the same as with FoldCase
+	for i := 0; i < 50; i++ {
+		s := random_cased_runeset(rand.Intn(6))
+		if got, want := s.FoldCaseSpecial(nil), s.FoldCase(); !got.Equal(want) {
+			t.Fatalf("%v.FoldCaseSpecial(nil) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func BenchmarkRuneSet_FoldCase(b *testing.B) {
+	s := FromRangeTable(unicode.Latin)
+	b.Run("SimpleFold", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			map_runes(s, fold_orbit)
+		}
+	})
+	b.Run("FoldCase", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s.FoldCase()
+		}
+	})
+}
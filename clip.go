@@ -0,0 +1,209 @@
+package ics
+
+import (
+	"iter"
+	"unicode/utf8"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Window is a read-only view of a set restricted to a window of values. The
+// boundaries of the set that fall within the window are shared with the set,
+// only the boundaries at the edges of the window are stored separately, so
+// creating a window does not allocate. The set must not be modified while the
+// window is in use.
+type Window[T constraints.Ordered] struct {
+	lo, hi T
+	head   bool // lo is the first boundary
+	body   []T  // boundaries of the set strictly within the window
+	tail   bool // hi is the last boundary
+}
+
+// NewWindow returns a view of s restricted to a window:
+//
+//   - if lo < hi, the window is a bounded interval [lo,hi), an open-ended
+//     interval of s that reaches hi is cut at hi
+//   - if lo >= hi, the window is a half-open interval [lo,... instead
+func NewWindow[S ~[]T, T constraints.Ordered](s S, lo, hi T) Window[T] {
+	w := Window[T]{lo: lo, hi: hi}
+	i := count_le(s, lo)
+	w.head = i&1 == 1
+	if lo >= hi {
+		w.body = s[i:]
+		return w
+	}
+	// j is the number of boundaries that are less than hi
+	j, _ := search(s, hi)
+	w.body = s[i:j]
+	w.tail = j&1 == 1
+	return w
+}
+
+// Len returns the number of boundaries of the windowed set.
+func (w Window[T]) Len() int {
+	n := len(w.body)
+	if w.head {
+		n++
+	}
+	if w.tail {
+		n++
+	}
+	return n
+}
+
+// At returns the i-th boundary of the windowed set.
+func (w Window[T]) At(i int) T {
+	if w.head {
+		if i == 0 {
+			return w.lo
+		}
+		i--
+	}
+	if w.tail && i == len(w.body) {
+		return w.hi
+	}
+	return w.body[i]
+}
+
+// Contains indicates if e is contained within the windowed set.
+func (w Window[T]) Contains(e T) bool {
+	if e < w.lo || (w.lo < w.hi && e >= w.hi) {
+		return false
+	}
+	i, ok := search(w.body, e)
+	if w.head {
+		i++
+	}
+	return (i&1 == 0) == ok
+}
+
+// IsEmpty indicates if the windowed set contains no elements.
+func (w Window[T]) IsEmpty() bool {
+	return w.Len() == 0
+}
+
+// Intervals returns an iterator over the half-open boundaries of the
+// intervals within the windowed set, see Intervals.
+func (w Window[T]) Intervals() iter.Seq2[T, T] {
+	return func(yield func(l, h T) bool) {
+		i, n := 0, w.Len()
+		for i+1 < n {
+			if !yield(w.At(i), w.At(i+1)) {
+				return
+			}
+			i += 2
+		}
+		if i < n {
+			yield(w.At(i), w.At(i))
+		}
+	}
+}
+
+// AppendTo appends the boundaries of the windowed set to dst and returns the
+// extended slice.
+func (w Window[T]) AppendTo(dst []T) []T {
+	if w.head {
+		dst = append(dst, w.lo)
+	}
+	dst = append(dst, w.body...)
+	if w.tail {
+		dst = append(dst, w.hi)
+	}
+	return dst
+}
+
+// Set returns a copy of the windowed set.
+func (w Window[T]) Set() Set[T] {
+	return w.AppendTo(make(Set[T], 0, w.Len()))
+}
+
+// Clip returns a set that contains the elements of s that fall within a
+// window, see NewWindow for the description of lo and hi.
+func Clip[S ~[]T, T constraints.Ordered](s S, lo, hi T) S {
+	w := NewWindow(s, lo, hi)
+	return w.AppendTo(make(S, 0, w.Len()))
+}
+
+// Split cuts s into the elements that are less than at and the elements that
+// are greater than or equal to at. The results share storage with s where
+// possible, both of them have their capacity limited to their length, so
+// appending to them does not overwrite s.
+func Split[S ~[]T, T constraints.Ordered](s S, at T) (below, above S) {
+	// j is the number of boundaries that are less than at
+	j, _ := search(s, at)
+	if j&1 == 0 {
+		below = s[:j:j]
+	} else {
+		below = append(s[:j:j], at)
+	}
+
+	k := count_le(s, at)
+	switch {
+	case k&1 == 0:
+		above = s[k:len(s):len(s)]
+	case s[k-1] == at:
+		above = s[k-1 : len(s) : len(s)]
+	default:
+		above = append(S{at}, s[k:]...)
+	}
+	return below, above
+}
+
+// domain_clip_range is Clip with an inclusive [lo,hi] window.
+func domain_clip_range[S ~[]T, T constraints.Integer](d Domain[T], s S, lo, hi T) S {
+	if hi == d.Max {
+		return Clip(s, lo, lo)
+	}
+	return Clip(s, lo, hi+1)
+}
+
+// Window returns a view of s restricted to a window, see NewWindow.
+func (s Set[T]) Window(lo, hi T) Window[T] {
+	return NewWindow(s, lo, hi)
+}
+
+// Clip returns a set that contains the elements of s that fall within a
+// window, see Clip.
+func (s Set[T]) Clip(lo, hi T) Set[T] {
+	return Clip(s, lo, hi)
+}
+
+// Split cuts s into the elements that are less than at and the elements that
+// are greater than or equal to at, see Split.
+func (s Set[T]) Split(at T) (below, above Set[T]) {
+	return Split(s, at)
+}
+
+// ClipRange returns a set that contains the runes of s within an inclusive
+// [rmin,rmax] range.
+func (s RuneSet) ClipRange(rmin, rmax rune) RuneSet {
+	if rmax < rmin {
+		panic("invalid rune range")
+	}
+	if rmin < 0 || rmax > utf8.MaxRune {
+		panic("unsupported rune value")
+	}
+	return domain_clip_range(RuneDomain, s, rmin, rmax)
+}
+
+// Split cuts s into the runes that are less than r and the runes that are
+// greater than or equal to r, see Split. AsciiSplit is a similar split at
+// 0x80 that also converts the lower part to an AsciiSet.
+func (s RuneSet) Split(r rune) (below, above RuneSet) {
+	return Split(s, r)
+}
+
+// ClipRange returns a set that contains the characters of s within an
+// inclusive [cmin,cmax] range.
+func (s AsciiSet) ClipRange(cmin, cmax byte) AsciiSet {
+	if cmax < cmin || cmax > 0x7f {
+		panic("invalid ascii range")
+	}
+	return domain_clip_range(AsciiDomain, s, cmin, cmax)
+}
+
+// Split cuts s into the characters that are less than c and the characters
+// that are greater than or equal to c, see Split.
+func (s AsciiSet) Split(c byte) (below, above AsciiSet) {
+	return Split(s, c)
+}
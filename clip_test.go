@@ -0,0 +1,93 @@
+package ics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestClip_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		s := random_int8set(r)
+		lo, hi := int8(r.Intn(256)-128), int8(r.Intn(256)-128)
+
+		window := Set[int8]{lo, hi}
+		if lo >= hi {
+			window = Set[int8]{lo}
+		}
+		want := Intersect(s, window)
+		if got := Clip(s, lo, hi); !got.Equal(want) {
+			t.Fatalf("Clip(%v, %d, %d) = %v, want %v", s, lo, hi, got, want)
+		}
+
+		w := s.Window(lo, hi)
+		if got := w.Set(); !got.Equal(want) || w.Len() != len(want) || w.IsEmpty() != (len(want) == 0) {
+			t.Fatalf("%v.Window(%d, %d) = %v, want %v", s, lo, hi, got, want)
+		}
+		for j := range want {
+			if w.At(j) != want[j] {
+				t.Fatalf("%v.Window(%d, %d).At(%d) = %d, want %d", s, lo, hi, j, w.At(j), want[j])
+			}
+		}
+		var intervals Set[int8]
+		for l, h := range w.Intervals() {
+			intervals = append(intervals, l)
+			if l != h {
+				intervals = append(intervals, h)
+			}
+		}
+		if !intervals.Equal(want) {
+			t.Fatalf("%v.Window(%d, %d).Intervals() = %v, want %v", s, lo, hi, intervals, want)
+		}
+		for v := math.MinInt8; v <= math.MaxInt8; v++ {
+			if w.Contains(int8(v)) != want.Contains(int8(v)) {
+				t.Fatalf("%v.Window(%d, %d).Contains(%d) = %v", s, lo, hi, v, !want.Contains(int8(v)))
+			}
+		}
+
+		at := lo
+		below, above := s.Split(at)
+		want_below, want_above := Intersect(s, Set[int8]{math.MinInt8, at}), Intersect(s, Set[int8]{at})
+		if at == math.MinInt8 {
+			want_below = Set[int8]{}
+		}
+		if !below.Equal(want_below) || !above.Equal(want_above) {
+			t.Fatalf("%v.Split(%d) = %v, %v, want %v, %v", s, at, below, above, want_below, want_above)
+		}
+		orig := s.Clone()
+		_ = append(below, 0)
+		_ = append(above, 0)
+		if !s.Equal(orig) {
+			t.Fatalf("appending to the results of %v.Split(%d) modifies the set", orig, at)
+		}
+	}
+}
+
+func TestRuneSet_ClipRange(t *testing.T) {
+	s := RuneSet{'0', '9' + 1, 'a'}
+	tests := []struct {
+		rmin, rmax rune
+		want       RuneSet
+	}{
+		{'5', 'c', RuneSet{'5', '9' + 1, 'a', 'd'}},
+		{'b', 0x10ffff, RuneSet{'b'}},
+		{0, '/', RuneSet{}},
+	}
+	for _, tt := range tests {
+		if got := s.ClipRange(tt.rmin, tt.rmax); !got.Equal(tt.want) {
+			t.Errorf("ClipRange(%q, %q) = %v, want %v", tt.rmin, tt.rmax, got, tt.want)
+		}
+	}
+
+	a := AsciiSet{'0', '9' + 1, 'a'}
+	if got := a.ClipRange('a', 0x7f); !got.Equal(AsciiSet{'a'}) {
+		t.Errorf("AsciiSet.ClipRange('a', 0x7f) = %v", got)
+	}
+
+	below, above := s.Split(0x80)
+	ascii, rest := s.AsciiSplit()
+	if !below.Equal(RuneSet{'0', '9' + 1, 'a', 0x80}) || !above.Equal(rest) || below.String() != ascii.String() {
+		t.Errorf("Split(0x80) = %v, %v, want %v, %v", below, above, ascii, rest)
+	}
+}
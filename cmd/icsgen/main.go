@@ -0,0 +1,287 @@
+// Command icsgen generates Go source files that declare containment sets.
+//
+// Usage:
+//
+//	icsgen [flags] range...
+//
+// Each range argument is a comma-separated list of items. Commas within
+// character literals, such as ',', do not separate items. An item is either a
+// single value v, an inclusive range lo..hi, or an open-ended range lo.. that
+// extends up to the maximum value of the element type. For float element
+// types, lo..hi denotes a half-open [lo,hi) interval instead.
+//
+// Values are written as Go literals: integers in any base (0x41, 65),
+// character literals ('a', '\n', 'é'), and U+XXXX codepoints. Use -- to
+// separate flags from ranges that start with a negative value.
+//
+// The command is meant to be used with go generate:
+//
+//	//go:generate go run github.com/adnsv/ics/cmd/icsgen -type ascii -name Digits -o digits.go '0'..'9'
+//
+// Flags:
+//
+//	-type   element type: rune, ascii, int, int8, ..., uint64, float32, float64 (default rune)
+//	-name   name of the declared variable (required)
+//	-pkg    package name (default $GOPACKAGE)
+//	-o      output file (default stdout)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/adnsv/ics"
+	"golang.org/x/exp/constraints"
+)
+
+func main() {
+	typ := flag.String("type", "rune", "element type")
+	name := flag.String("name", "", "name of the declared variable")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name")
+	out := flag.String("o", "", "output file")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: icsgen [flags] range...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *name == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := generate(*typ, *pkg, *name, flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "icsgen: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*out, src, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "icsgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(typ, pkg, name string, args []string) ([]byte, error) {
+	items := []string{}
+	for _, arg := range args {
+		r, err := split_items(arg)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, r...)
+	}
+
+	switch typ {
+	case "rune":
+		s, err := discrete(ics.RuneDomain, items)
+		return emit(pkg, name, ics.RuneSet(s), err)
+	case "ascii":
+		s, err := discrete(ics.AsciiDomain, items)
+		return emit(pkg, name, ics.AsciiSet(s), err)
+	case "int":
+		s, err := discrete(ics.IntDomain, items)
+		return emit(pkg, name, s, err)
+	case "int8":
+		s, err := discrete(ics.Int8Domain, items)
+		return emit(pkg, name, s, err)
+	case "int16":
+		s, err := discrete(ics.Int16Domain, items)
+		return emit(pkg, name, s, err)
+	case "int32":
+		s, err := discrete(ics.Int32Domain, items)
+		return emit(pkg, name, s, err)
+	case "int64":
+		s, err := discrete(ics.Int64Domain, items)
+		return emit(pkg, name, s, err)
+	case "uint":
+		s, err := discrete(ics.UintDomain, items)
+		return emit(pkg, name, s, err)
+	case "uint8", "byte":
+		s, err := discrete(ics.Uint8Domain, items)
+		return emit(pkg, name, s, err)
+	case "uint16":
+		s, err := discrete(ics.Uint16Domain, items)
+		return emit(pkg, name, s, err)
+	case "uint32":
+		s, err := discrete(ics.Uint32Domain, items)
+		return emit(pkg, name, s, err)
+	case "uint64":
+		s, err := discrete(ics.Uint64Domain, items)
+		return emit(pkg, name, s, err)
+	case "float32":
+		s, err := continuous[float32](items)
+		return emit(pkg, name, s, err)
+	case "float64":
+		s, err := continuous[float64](items)
+		return emit(pkg, name, s, err)
+	default:
+		return nil, fmt.Errorf("unsupported type %q", typ)
+	}
+}
+
+// split_items splits a range argument into its comma-separated items. Commas
+// within character literals, such as ',', do not separate items.
+func split_items(arg string) ([]string, error) {
+	items := []string{}
+	add := func(item string) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	start := 0
+	for i := 0; i < len(arg); i++ {
+		switch arg[i] {
+		case '\'':
+			j := skip_literal(arg, i)
+			if j < 0 {
+				return nil, fmt.Errorf("unterminated character literal %s", arg[i:])
+			}
+			i = j - 1
+		case ',':
+			add(arg[start:i])
+			start = i + 1
+		}
+	}
+	add(arg[start:])
+	return items, nil
+}
+
+// cut_range slices an item around the first .. that is not within a
+// character literal.
+func cut_range(item string) (lo, hi string, found bool) {
+	for i := 0; i < len(item); i++ {
+		switch {
+		case item[i] == '\'':
+			if j := skip_literal(item, i); j > 0 {
+				i = j - 1
+			}
+		case strings.HasPrefix(item[i:], ".."):
+			return item[:i], item[i+2:], true
+		}
+	}
+	return item, "", false
+}
+
+// skip_literal returns the index past the end of the character literal that
+// starts at s[i], or -1 if the literal is not terminated.
+func skip_literal(s string, i int) int {
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '\'':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func emit[S ~[]T, T constraints.Ordered](pkg, name string, s S, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return ics.GoSource(pkg, name, s)
+}
+
+// discrete builds a set of inclusive ranges.
+func discrete[T constraints.Integer](d ics.Domain[T], items []string) (ics.Set[T], error) {
+	s := ics.NewDomainSet(d)
+	for _, item := range items {
+		lo, hi, found := cut_range(item)
+		min, err := parse_int(d, lo)
+		if err != nil {
+			return nil, err
+		}
+		max := min
+		if found {
+			if hi == "" {
+				max = d.Max
+			} else if max, err = parse_int(d, hi); err != nil {
+				return nil, err
+			}
+		}
+		if max < min {
+			return nil, fmt.Errorf("invalid range %q", item)
+		}
+		s.InsertRange(min, max)
+	}
+	return s.Set, nil
+}
+
+// continuous builds a set of half-open intervals.
+func continuous[T constraints.Float](items []string) (ics.Set[T], error) {
+	var b ics.Builder[T]
+	for _, item := range items {
+		lo, hi, found := cut_range(item)
+		l, err := strconv.ParseFloat(lo, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", lo)
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid interval %q, float types require lo..hi or lo..", item)
+		}
+		h := l
+		if hi != "" {
+			if h, err = strconv.ParseFloat(hi, 64); err != nil {
+				return nil, fmt.Errorf("invalid value %q", hi)
+			}
+			if h <= l {
+				return nil, fmt.Errorf("invalid interval %q", item)
+			}
+		}
+		b.Add(T(l), T(h))
+	}
+	return b.Build(), nil
+}
+
+func parse_int[T constraints.Integer](d ics.Domain[T], s string) (T, error) {
+	var v T
+	switch {
+	case strings.HasPrefix(s, "'"):
+		r, err := strconv.Unquote(s)
+		if err != nil || len([]rune(r)) != 1 {
+			return 0, fmt.Errorf("invalid character literal %s", s)
+		}
+		c := []rune(r)[0]
+		if v = T(c); rune(v) != c {
+			return 0, fmt.Errorf("value %s is out of range", s)
+		}
+	case strings.HasPrefix(s, "U+") || strings.HasPrefix(s, "u+"):
+		x, err := strconv.ParseUint(s[2:], 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid codepoint %s", s)
+		}
+		if v = T(x); v < 0 || uint64(v) != x {
+			return 0, fmt.Errorf("value %s is out of range", s)
+		}
+	case strings.HasPrefix(s, "-"):
+		x, err := strconv.ParseInt(s, 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %s", s)
+		}
+		if v = T(x); int64(v) != x {
+			return 0, fmt.Errorf("value %s is out of range", s)
+		}
+	default:
+		x, err := strconv.ParseUint(s, 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %s", s)
+		}
+		if v = T(x); v < 0 || uint64(v) != x {
+			return 0, fmt.Errorf("value %s is out of range", s)
+		}
+	}
+	if !d.Contains(v) {
+		return 0, fmt.Errorf("value %s is out of range", s)
+	}
+	return v, nil
+}
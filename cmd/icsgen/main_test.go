@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adnsv/ics"
+	"golang.org/x/exp/constraints"
+)
+
+func source[S ~[]T, T constraints.Ordered](s S) string {
+	src, err := ics.GoSource("x", "C", s)
+	if err != nil {
+		panic(err)
+	}
+	return string(src)
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		typ  string
+		args []string
+		want string
+	}{
+		{"rune", []string{"'a'..'z'", "'_'"}, source(ics.RuneSet{'_', '_' + 1, 'a', 'z' + 1})},
+		{"rune", []string{"'0'..'9', U+0041..U+005A"}, source(ics.RuneSet{'0', '9' + 1, 'A', 'Z' + 1})},
+		{"rune", []string{"','"}, source(ics.RuneSet{',', ',' + 1})},
+		{"rune", []string{"',', ';'"}, source(ics.RuneSet{',', ',' + 1, ';', ';' + 1})},
+		{"rune", []string{"'\\'', '\\\\'"}, source(ics.RuneSet{'\'', '\'' + 1, '\\', '\\' + 1})},
+		{"rune", []string{"'.'..'/'"}, source(ics.RuneSet{'.', '/' + 1})},
+		{"rune", []string{"'é'", "0x10000.."}, source(ics.RuneSet{'é', 'é' + 1, 0x10000})},
+		{"rune", []string{"0x10ffff"}, source(ics.RuneSet{0x10ffff})},
+		{"ascii", []string{"'0'..'9'"}, source(ics.AsciiSet{'0', '9' + 1})},
+		{"ascii", []string{"0x7f", "' '.."}, source(ics.AsciiSet{' '})},
+		{"int8", []string{"-128..-1", "100.."}, source(ics.Set[int8]{-128, 0, 100})},
+		{"uint16", []string{"8000..8999,0x100"}, source(ics.Set[uint16]{0x100, 0x101, 8000, 9000})},
+		{"uint64", []string{"0.."}, source(ics.Set[uint64]{0})},
+		{"float64", []string{"-1.5..0.25", "1e6.."}, source(ics.Set[float64]{-1.5, 0.25, 1e6})},
+		{"float32", []string{"0..1, 0.5..2"}, source(ics.Set[float32]{0, 2})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := generate(tt.typ, "x", "C", tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("generate() = \n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		typ  string
+		args []string
+		want string
+	}{
+		{"string", []string{"1"}, `unsupported type "string"`},
+		{"rune", []string{"'ab'"}, "invalid character literal 'ab'"},
+		{"rune", []string{"'a"}, "unterminated character literal 'a"},
+		{"rune", []string{"',"}, "unterminated character literal ',"},
+		{"rune", []string{"'z'..'a'"}, `invalid range "'z'..'a'"`},
+		{"rune", []string{"0x110000"}, "value 0x110000 is out of range"},
+		{"rune", []string{"U+XYZ"}, "invalid codepoint U+XYZ"},
+		{"rune", []string{"abc"}, "invalid value abc"},
+		{"ascii", []string{"'é'"}, "value 'é' is out of range"},
+		{"ascii", []string{"0x80"}, "value 0x80 is out of range"},
+		{"int8", []string{"200"}, "value 200 is out of range"},
+		{"int8", []string{"-129"}, "value -129 is out of range"},
+		{"uint8", []string{"-1"}, "value -1 is out of range"},
+		{"float64", []string{"1"}, `invalid interval "1", float types require lo..hi or lo..`},
+		{"float64", []string{"2..1"}, `invalid interval "2..1"`},
+		{"float64", []string{"x..1"}, `invalid value "x"`},
+		{"float64", []string{"0..y"}, `invalid value "y"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+strings.Join(tt.args, " "), func(t *testing.T) {
+			_, err := generate(tt.typ, "x", "C", tt.args)
+			if err == nil {
+				t.Fatalf("generate() succeeded, want error %q", tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("generate() error = %q, want %q", err, tt.want)
+			}
+		})
+	}
+}
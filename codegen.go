@@ -0,0 +1,117 @@
+package ics
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"math"
+	"reflect"
+	"strconv"
+
+	"golang.org/x/exp/constraints"
+)
+
+// GoSource produces a gofmt'ed Go source file for the package pkg that
+// declares a variable with the given name initialized with the elements of s.
+//
+// The variable is declared as ics.RuneSet or ics.AsciiSet if s is of the
+// corresponding type, otherwise it is declared as ics.Set[T], where T must be a
+// predeclared type. When pkg is "ics" itself, the type is not qualified.
+func GoSource[S ~[]T, T constraints.Ordered](pkg, name string, s S) ([]byte, error) {
+	qualifier := "ics."
+	if pkg == "ics" {
+		qualifier = ""
+	}
+
+	var typ, desc string
+	var elem func(w *bytes.Buffer, i int) error
+	switch v := any(s).(type) {
+	case RuneSet:
+		typ, desc = "RuneSet", v.String()
+		elem = func(w *bytes.Buffer, i int) error {
+			write_go_char(w, v[i], i&1 == 1)
+			return nil
+		}
+	case AsciiSet:
+		typ, desc = "AsciiSet", v.String()
+		elem = func(w *bytes.Buffer, i int) error {
+			write_go_char(w, rune(v[i]), i&1 == 1)
+			return nil
+		}
+	default:
+		t := reflect.TypeOf(s).Elem()
+		if t.PkgPath() != "" {
+			return nil, fmt.Errorf("ics: unsupported element type %v", t)
+		}
+		typ, desc = "Set["+t.Name()+"]", Set[T](s).String()
+		elem = func(w *bytes.Buffer, i int) error {
+			return write_go_value(w, s[i])
+		}
+	}
+
+	w := bytes.Buffer{}
+	w.WriteString("// Code generated by icsgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&w, "package %s\n\n", pkg)
+	if qualifier != "" {
+		w.WriteString("import \"github.com/adnsv/ics\"\n\n")
+	}
+	fmt.Fprintf(&w, "// %s contains %s\n", name, desc)
+	fmt.Fprintf(&w, "var %s = %s%s{\n", name, qualifier, typ)
+	for i := range s {
+		if err := elem(&w, i); err != nil {
+			return nil, err
+		}
+		if i&1 == 1 || i+1 == len(s) {
+			w.WriteString(",\n")
+		} else {
+			w.WriteString(", ")
+		}
+	}
+	w.WriteString("}\n")
+
+	return format.Source(w.Bytes())
+}
+
+// write_go_char writes a character literal. Upper boundaries are written as
+// the last contained character plus one, which reads like an inclusive range.
+func write_go_char(w *bytes.Buffer, r rune, upper bool) {
+	if upper {
+		r--
+	}
+	switch {
+	case r == '\'':
+		w.WriteString(`'\''`)
+	case r == '\\':
+		w.WriteString(`'\\'`)
+	case r >= 0x20 && r < 0x7f:
+		w.WriteByte('\'')
+		w.WriteByte(byte(r))
+		w.WriteByte('\'')
+	case r >= 0xd800 && r <= 0xdfff:
+		// surrogate halves are not valid in character literals
+		fmt.Fprintf(w, "0x%X", r)
+	default:
+		w.WriteByte('\'')
+		print_rune(w, r)
+		w.WriteByte('\'')
+	}
+	if upper {
+		w.WriteString(" + 1")
+	}
+}
+
+func write_go_value[T constraints.Ordered](w *bytes.Buffer, v T) error {
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("ics: unsupported value %v", f)
+		}
+		w.WriteString(strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()))
+	case reflect.String:
+		w.WriteString(strconv.Quote(rv.String()))
+	default:
+		fmt.Fprintf(w, "%v", v)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package ics
+
+import (
+	"testing"
+)
+
+func TestGoSource(t *testing.T) {
+	got, err := GoSource("tables", "Ident", RuneSet{'$', '$' + 1, '0', '9' + 1, 'a', 'z' + 1, 0xd800, 0xe000, 0x10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `// Code generated by icsgen. DO NOT EDIT.
+
+package tables
+
+import "github.com/adnsv/ics"
+
+// Ident contains $0-9a-z\uD800-\uDFFF\U00010000-\U0010FFFF
+var Ident = ics.RuneSet{
+	'$', '$' + 1,
+	'0', '9' + 1,
+	'a', 'z' + 1,
+	0xD800, 0xDFFF + 1,
+	'\U00010000',
+}
+`
+	if string(got) != want {
+		t.Errorf("GoSource() = \n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGoSource_Set(t *testing.T) {
+	got, err := GoSource("ics", "limits", Set[float64]{-1.5, 0.25, 1e6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `// Code generated by icsgen. DO NOT EDIT.
+
+package ics
+
+// limits contains [-1.5,0.25)[1e+06...
+var limits = Set[float64]{
+	-1.5, 0.25,
+	1e+06,
+}
+`
+	if string(got) != want {
+		t.Errorf("GoSource() = \n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,285 @@
+package ics
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"unicode/utf8"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Domain describes a bounded range [Min..Max] of discrete values a containment
+// set operates on.
+//
+// The successor of Max may not be representable in T, so sets that are bound
+// to a domain never use it as an upper boundary. Instead, intervals that reach
+// Max are stored as open-ended.
+type Domain[T constraints.Integer] struct {
+	Min, Max T
+}
+
+// Ready-made domains for built-in integer types.
+var (
+	IntDomain     = Domain[int]{math.MinInt, math.MaxInt}
+	Int8Domain    = Domain[int8]{math.MinInt8, math.MaxInt8}
+	Int16Domain   = Domain[int16]{math.MinInt16, math.MaxInt16}
+	Int32Domain   = Domain[int32]{math.MinInt32, math.MaxInt32}
+	Int64Domain   = Domain[int64]{math.MinInt64, math.MaxInt64}
+	UintDomain    = Domain[uint]{0, math.MaxUint}
+	Uint8Domain   = Domain[uint8]{0, math.MaxUint8}
+	Uint16Domain  = Domain[uint16]{0, math.MaxUint16}
+	Uint32Domain  = Domain[uint32]{0, math.MaxUint32}
+	Uint64Domain  = Domain[uint64]{0, math.MaxUint64}
+	UintptrDomain = Domain[uintptr]{0, ^uintptr(0)}
+)
+
+// Domains used by RuneSet and AsciiSet.
+var (
+	RuneDomain  = Domain[rune]{0, utf8.MaxRune}
+	AsciiDomain = Domain[byte]{0, 0x7f}
+)
+
+// Errors returned by the Try* variants of the mutators, such as
+// RuneSet.TryInsertRange, for the inputs that make their panicking forms panic.
+var (
+	ErrInvalidRange = errors.New("ics: invalid range")
+	ErrOutOfDomain  = errors.New("ics: value out of domain")
+)
+
+// NewDomain returns a domain for values [min..max].
+func NewDomain[T constraints.Integer](min, max T) Domain[T] {
+	if max < min {
+		panic("invalid domain")
+	}
+	return Domain[T]{min, max}
+}
+
+// FullDomain returns a domain that spans all values of T.
+func FullDomain[T constraints.Integer]() Domain[T] {
+	max := ^T(0)
+	if max > 0 {
+		return Domain[T]{0, max}
+	}
+	// signed: shift a single bit up to the position right below the sign bit
+	max = 1
+	for max<<1 > 0 {
+		max <<= 1
+	}
+	max += max - 1
+	return Domain[T]{-max - 1, max}
+}
+
+// Contains indicates if v is within the domain.
+func (d Domain[T]) Contains(v T) bool {
+	return d.Min <= v && v <= d.Max
+}
+
+// Next returns the successor of v. Returns false if v is not less than Max.
+func (d Domain[T]) Next(v T) (T, bool) {
+	if v >= d.Max {
+		return v, false
+	}
+	return v + 1, true
+}
+
+// domain_insert_range inserts a valid inclusive [lo,hi] range into s.
+func domain_insert_range[S ~[]T, T constraints.Integer](d Domain[T], s *S, lo, hi T) {
+	if hi == d.Max {
+		// insert open-ended interval
+		InsertInterval(s, lo, lo)
+	} else {
+		// insert fully-bound interval
+		InsertInterval(s, lo, hi+1)
+	}
+}
+
+// domain_remove_range removes a valid inclusive [lo,hi] range from s.
+func domain_remove_range[S ~[]T, T constraints.Integer](d Domain[T], s *S, lo, hi T) {
+	if hi == d.Max {
+		// remove open-ended interval
+		RemoveInterval(s, lo, lo)
+	} else {
+		// remove fully-bound interval
+		RemoveInterval(s, lo, hi+1)
+	}
+}
+
+// domain_check_range validates an inclusive [lo,hi] range for the Try*
+// mutators.
+func domain_check_range[T constraints.Integer](d Domain[T], lo, hi T) error {
+	if hi < lo {
+		return fmt.Errorf("%w: [%v..%v]", ErrInvalidRange, lo, hi)
+	}
+	if !d.Contains(lo) || !d.Contains(hi) {
+		if lo == hi {
+			return fmt.Errorf("%w: %v is outside of [%v..%v]", ErrOutOfDomain, lo, d.Min, d.Max)
+		}
+		return fmt.Errorf("%w: [%v..%v] is outside of [%v..%v]", ErrOutOfDomain, lo, hi, d.Min, d.Max)
+	}
+	return nil
+}
+
+func domain_try_insert_range[S ~[]T, T constraints.Integer](d Domain[T], s *S, lo, hi T) error {
+	if err := domain_check_range(d, lo, hi); err != nil {
+		return err
+	}
+	domain_insert_range(d, s, lo, hi)
+	return nil
+}
+
+func domain_try_remove_range[S ~[]T, T constraints.Integer](d Domain[T], s *S, lo, hi T) error {
+	if err := domain_check_range(d, lo, hi); err != nil {
+		return err
+	}
+	domain_remove_range(d, s, lo, hi)
+	return nil
+}
+
+func domain_enumerate_ranges[S ~[]T, T constraints.Integer](d Domain[T], s S, f func(lo, hi T)) {
+	i, n := 0, len(s)
+	for i+1 < n {
+		f(s[i], s[i+1]-1)
+		i += 2
+	}
+	if i < n {
+		f(s[i], d.Max)
+	}
+}
+
+func domain_invert[S ~[]T, T constraints.Integer](d Domain[T], s S) S {
+	if len(s) == 0 {
+		return S{d.Min}
+	} else if s[0] == d.Min {
+		return s[1:]
+	} else {
+		return append(S{d.Min}, s...)
+	}
+}
+
+// domain_count returns the number of elements in s. The result saturates at
+// math.MaxUint64 for a full 64-bit domain.
+func domain_count[S ~[]T, T constraints.Integer](d Domain[T], s S) uint64 {
+	var r, carry uint64
+	add := func(v uint64) {
+		var c uint64
+		r, c = bits.Add64(r, v, 0)
+		carry |= c
+	}
+	i, n := 0, len(s)
+	for i+1 < n {
+		add(uint64(s[i+1]) - uint64(s[i]))
+		i += 2
+	}
+	if i < n {
+		add(uint64(d.Max) - uint64(s[i]))
+		add(1)
+	}
+	if carry != 0 {
+		return math.MaxUint64
+	}
+	return r
+}
+
+// DomainSet is a containment set bound to a Domain. Similarly to RuneSet and
+// AsciiSet, it provides insertion and enumeration API that operates with fully
+// closed [min,max] ranges instead of half-open intervals.
+type DomainSet[T constraints.Integer] struct {
+	Domain Domain[T]
+	Set    Set[T]
+}
+
+// NewDomainSet returns an empty set bound to the domain d.
+func NewDomainSet[T constraints.Integer](d Domain[T]) DomainSet[T] {
+	return DomainSet[T]{Domain: d}
+}
+
+// Contains indicates if v is contained within s.
+func (s DomainSet[T]) Contains(v T) bool {
+	return s.Domain.Contains(v) && Contains(s.Set, v)
+}
+
+// Insert adds v to the set.
+func (s *DomainSet[T]) Insert(v T) {
+	if !s.Domain.Contains(v) {
+		panic("value out of domain")
+	}
+	domain_insert_range(s.Domain, &s.Set, v, v)
+}
+
+// TryInsert is Insert that returns an error wrapping ErrOutOfDomain instead of
+// panicking.
+func (s *DomainSet[T]) TryInsert(v T) error {
+	return domain_try_insert_range(s.Domain, &s.Set, v, v)
+}
+
+// InsertRange inserts an inclusive [min,max] range of values into the set.
+func (s *DomainSet[T]) InsertRange(min, max T) {
+	if max < min {
+		panic("invalid range")
+	}
+	if !s.Domain.Contains(min) || !s.Domain.Contains(max) {
+		panic("value out of domain")
+	}
+	domain_insert_range(s.Domain, &s.Set, min, max)
+}
+
+// TryInsertRange is InsertRange that returns an error wrapping ErrInvalidRange
+// or ErrOutOfDomain instead of panicking.
+func (s *DomainSet[T]) TryInsertRange(min, max T) error {
+	return domain_try_insert_range(s.Domain, &s.Set, min, max)
+}
+
+// Remove removes v from the set.
+func (s *DomainSet[T]) Remove(v T) {
+	if !s.Domain.Contains(v) {
+		panic("value out of domain")
+	}
+	domain_remove_range(s.Domain, &s.Set, v, v)
+}
+
+// TryRemove is Remove that returns an error wrapping ErrOutOfDomain instead of
+// panicking.
+func (s *DomainSet[T]) TryRemove(v T) error {
+	return domain_try_remove_range(s.Domain, &s.Set, v, v)
+}
+
+// RemoveRange removes an inclusive [min,max] range of values from the set.
+func (s *DomainSet[T]) RemoveRange(min, max T) {
+	if max < min {
+		panic("invalid range")
+	}
+	if !s.Domain.Contains(min) || !s.Domain.Contains(max) {
+		panic("value out of domain")
+	}
+	domain_remove_range(s.Domain, &s.Set, min, max)
+}
+
+// TryRemoveRange is RemoveRange that returns an error wrapping ErrInvalidRange
+// or ErrOutOfDomain instead of panicking.
+func (s *DomainSet[T]) TryRemoveRange(min, max T) error {
+	return domain_try_remove_range(s.Domain, &s.Set, min, max)
+}
+
+// EnumerateRanges is a functional enumerator for all the continuous inclusive
+// [min,max] ranges contained within the set.
+func (s DomainSet[T]) EnumerateRanges(f func(min, max T)) {
+	domain_enumerate_ranges(s.Domain, s.Set, f)
+}
+
+// Inverted returns a containment set with inverted logic.
+func (s DomainSet[T]) Inverted() DomainSet[T] {
+	return DomainSet[T]{s.Domain, domain_invert(s.Domain, s.Set)}
+}
+
+// Complement inverts the logic of the set in place.
+func (s *DomainSet[T]) Complement() {
+	s.Set = domain_invert(s.Domain, s.Set)
+}
+
+// Count returns the number of values contained in s. The result saturates at
+// math.MaxUint64 when all values of a 64-bit domain are contained.
+func (s DomainSet[T]) Count() uint64 {
+	return domain_count(s.Domain, s.Set)
+}
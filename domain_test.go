@@ -0,0 +1,148 @@
+package ics
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestFullDomain(t *testing.T) {
+	if got := FullDomain[int8](); got != Int8Domain {
+		t.Errorf("FullDomain[int8]() = %v, want %v", got, Int8Domain)
+	}
+	if got := FullDomain[int64](); got != Int64Domain {
+		t.Errorf("FullDomain[int64]() = %v, want %v", got, Int64Domain)
+	}
+	if got := FullDomain[uint16](); got != Uint16Domain {
+		t.Errorf("FullDomain[uint16]() = %v, want %v", got, Uint16Domain)
+	}
+	if got := FullDomain[uintptr](); got != UintptrDomain {
+		t.Errorf("FullDomain[uintptr]() = %v, want %v", got, UintptrDomain)
+	}
+}
+
+func TestDomainSet(t *testing.T) {
+	ports := NewDomainSet(NewDomain[uint16](0, 65535))
+	ports.InsertRange(80, 80)
+	ports.InsertRange(8000, 8999)
+	ports.InsertRange(60000, 65535)
+
+	if want := (Set[uint16]{80, 81, 8000, 9000, 60000}); !slices.Equal(ports.Set, want) {
+		t.Errorf("ports = %v, want %v", ports.Set, want)
+	}
+	if got := ports.Count(); got != 1+1000+5536 {
+		t.Errorf("ports.Count() = %d, want %d", got, 1+1000+5536)
+	}
+
+	inv := ports.Inverted()
+	if want := (Set[uint16]{0, 80, 81, 8000, 9000, 60000}); !slices.Equal(inv.Set, want) {
+		t.Errorf("ports.Inverted() = %v, want %v", inv.Set, want)
+	}
+	if got := inv.Count(); got != 65536-(1+1000+5536) {
+		t.Errorf("ports.Inverted().Count() = %d, want %d", got, 65536-(1+1000+5536))
+	}
+
+	var got [][2]uint16
+	inv.EnumerateRanges(func(min, max uint16) {
+		got = append(got, [2]uint16{min, max})
+	})
+	want := [][2]uint16{{0, 79}, {81, 7999}, {9000, 59999}}
+	if !slices.Equal(got, want) {
+		t.Errorf("EnumerateRanges = %v, want %v", got, want)
+	}
+
+	ports.RemoveRange(8500, 65535)
+	ports.Complement()
+	if want := (Set[uint16]{0, 80, 81, 8000, 8500}); !slices.Equal(ports.Set, want) {
+		t.Errorf("complemented = %v, want %v", ports.Set, want)
+	}
+}
+
+func TestDomainSet_Bounded(t *testing.T) {
+	digits := NewDomainSet(NewDomain(0, 9))
+	digits.InsertRange(5, 9)
+	if digits.Contains(10) || digits.Contains(-1) || !digits.Contains(9) {
+		t.Errorf("containment is not bound by the domain")
+	}
+	if got := digits.Count(); got != 5 {
+		t.Errorf("Count() = %d, want 5", got)
+	}
+}
+
+func TestDomainSet_Count(t *testing.T) {
+	tests := []struct {
+		s    DomainSet[int64]
+		want uint64
+	}{
+		{DomainSet[int64]{Int64Domain, nil}, 0},
+		{DomainSet[int64]{Int64Domain, Set[int64]{-5, 5}}, 10},
+		{DomainSet[int64]{Int64Domain, Set[int64]{math.MaxInt64}}, 1},
+		{DomainSet[int64]{Int64Domain, Set[int64]{0}}, 1 << 63},
+		{DomainSet[int64]{Int64Domain, Set[int64]{math.MinInt64, math.MaxInt64}}, math.MaxUint64},
+		{DomainSet[int64]{Int64Domain, Set[int64]{math.MinInt64}}, math.MaxUint64},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Count(); got != tt.want {
+			t.Errorf("%v.Count() = %d, want %d", tt.s.Set, got, tt.want)
+		}
+	}
+}
+
+func TestTryMutators(t *testing.T) {
+	digits := NewDomainSet(NewDomain(0, 9))
+	var r RuneSet
+	var a AsciiSet
+	var rb RuneSetBuilder
+	var ab AsciiSetBuilder
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"DomainSet.TryInsert", digits.TryInsert(10), ErrOutOfDomain},
+		{"DomainSet.TryInsertRange", digits.TryInsertRange(5, 3), ErrInvalidRange},
+		{"DomainSet.TryInsertRange", digits.TryInsertRange(-1, 3), ErrOutOfDomain},
+		{"DomainSet.TryInsertRange", digits.TryInsertRange(3, 9), nil},
+		{"DomainSet.TryRemove", digits.TryRemove(-1), ErrOutOfDomain},
+		{"DomainSet.TryRemoveRange", digits.TryRemoveRange(5, 5), nil},
+		{"RuneSet.TryInsert", r.TryInsert(-1), ErrOutOfDomain},
+		{"RuneSet.TryInsertRange", r.TryInsertRange('z', 'a'), ErrInvalidRange},
+		{"RuneSet.TryInsertRange", r.TryInsertRange('a', 0x110000), ErrOutOfDomain},
+		{"RuneSet.TryInsertRange", r.TryInsertRange('a', 'z'), nil},
+		{"RuneSet.TryRemove", r.TryRemove('q'), nil},
+		{"RuneSet.TryRemoveRange", r.TryRemoveRange('c', 'b'), ErrInvalidRange},
+		{"AsciiSet.TryInsert", a.TryInsert(0x80), ErrOutOfDomain},
+		{"AsciiSet.TryInsertRange", a.TryInsertRange('0', 0xff), ErrOutOfDomain},
+		{"AsciiSet.TryInsertRange", a.TryInsertRange('0', '9'), nil},
+		{"AsciiSet.TryRemove", a.TryRemove('5'), nil},
+		{"AsciiSet.TryRemoveRange", a.TryRemoveRange('9', '0'), ErrInvalidRange},
+		{"RuneSetBuilder.TryAdd", rb.TryAdd(0x110000), ErrOutOfDomain},
+		{"RuneSetBuilder.TryAddRange", rb.TryAddRange('a', 'b'), nil},
+		{"AsciiSetBuilder.TryAdd", ab.TryAdd(0x80), ErrOutOfDomain},
+		{"AsciiSetBuilder.TryAddRange", ab.TryAddRange('b', 'a'), ErrInvalidRange},
+	}
+	for _, tt := range tests {
+		if !errors.Is(tt.err, tt.want) {
+			t.Errorf("%s() = %v, want %v", tt.name, tt.err, tt.want)
+		}
+	}
+
+	// failed calls leave the sets unmodified
+	if got := digits.Set; !got.Equal(Set[int]{3, 5, 6}) {
+		t.Errorf("DomainSet = %v", got)
+	}
+	if got := r; !got.Equal(RuneSet{'a', 'q', 'r', 'z' + 1}) {
+		t.Errorf("RuneSet = %v", got)
+	}
+	if got := a; !got.Equal(AsciiSet{'0', '5', '6', '9' + 1}) {
+		t.Errorf("AsciiSet = %v", got)
+	}
+	if got := rb.Build(); !got.Equal(RuneSet{'a', 'c'}) {
+		t.Errorf("RuneSetBuilder.Build() = %v", got)
+	}
+	if got := ab.Build(); len(got) != 0 {
+		t.Errorf("AsciiSetBuilder.Build() = %v", got)
+	}
+}
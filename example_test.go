@@ -2,6 +2,7 @@ package ics
 
 import (
 	"fmt"
+	"unicode"
 )
 
 func ExampleInsert() {
@@ -81,3 +82,24 @@ func ExampleInsert() {
 	// perl_w   [0-9A-Z_a-z]     "0:A[_`a{"       "\x000:A[_`a{"     "0:A[_`a{"         "\x000:A[_`a{"
 
 }
+
+func ExampleRuneSet_FormatClass() {
+	greek := FromRangeTable(unicode.Greek)
+	word := RuneSet{'0', '9' + 1, 'A', 'Z' + 1, '_', '_' + 1, 'a', 'z' + 1}
+	s := MergeRuneSets(word, greek, RuneSet{'-', '-' + 1, ']', ']' + 1})
+
+	fmt.Println(s.FormatClass(RE2))
+	fmt.Println(s.FormatClass(PCRE))
+	fmt.Println(s.FormatClass(JavaScript))
+	fmt.Println(s.FormatClass(Java))
+	fmt.Println(word.Inverted().FormatClass(RE2))
+	fmt.Println(RuneSet{'!', '!' + 1, '-', '-' + 1, ']', ']' + 1}.FormatClass(POSIX))
+
+	// Output:
+	// [\p{Greek}\w\-\]]
+	// [\p{Greek}\w\-\]]
+	// [\p{sc=Greek}\w\-\]]
+	// [\p{IsGreek}\w\-\]]
+	// \W
+	// []!-]
+}
@@ -0,0 +1,383 @@
+package ics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// FormatClass produces a character class that matches exactly the runes of s
+// in the syntax of the dialect d. The result can be parsed back with
+// ParseRuneSet.
+//
+// The class is written in the shortest form the formatter can find: it is
+// negated when the inverted set is shorter to write, and named classes, such
+// as \d, [:alpha:] or \p{Greek}, replace the ranges they cover whenever that
+// saves space. Regular expression dialects assume default flags, e.g. \w and
+// [:alpha:] match ASCII characters only. Unicode classes are taken from the
+// tables of the unicode package, they match exactly as long as the regular
+// expression engine uses the same version of Unicode.
+//
+// The POSIX and Glob dialects have no escapes for non-printable characters, so
+// all characters are written as is. Surrogate halves have no UTF-8 encoding
+// and can not be written in these dialects, they are ignored.
+//
+// For the Plain dialect, FormatClass returns the same result as String.
+func (s RuneSet) FormatClass(d Dialect) string {
+	return format_class(s, d)
+}
+
+// FormatClass produces a character class that matches exactly the characters
+// of s in the syntax of the dialect d. See RuneSet.FormatClass for details.
+func (s AsciiSet) FormatClass(d Dialect) string {
+	if d == Plain {
+		return s.String()
+	}
+	r := make(RuneSet, len(s), len(s)+1)
+	for i, c := range s {
+		r[i] = rune(c)
+	}
+	if len(r)&1 == 1 {
+		r = append(r, 0x80)
+	}
+	return format_class(r, d)
+}
+
+func format_class(s RuneSet, d Dialect) string {
+	switch d {
+	case Plain:
+		return s.String()
+	case RE2, PCRE, JavaScript, Java, POSIX, Glob:
+	default:
+		panic("unsupported dialect")
+	}
+
+	inv := s.Inverted()
+	if d == POSIX || d == Glob {
+		surrogates := RuneSet{0xd800, 0xdfff + 1}
+		s = Difference(s, surrogates)
+		inv = Difference(inv, surrogates)
+	}
+
+	f := class_formatter{d: d, named: named_classes(d)}
+	pos := f.format(s, false)
+	neg := f.format(inv, true)
+	if neg != "" && (pos == "" || len(neg) < len(pos)) {
+		return neg
+	}
+	return pos
+}
+
+// named_class is a class that can be referred to by name.
+type named_class struct {
+	name   string
+	set    RuneSet
+	escape bool // the name is an escape that is also valid outside of brackets
+}
+
+type class_formatter struct {
+	d     Dialect
+	named []named_class
+	buf   strings.Builder
+	tmp   RuneSet
+}
+
+// format produces a bracketed class for s, or an escape if s is matched by a
+// single named class. Returns an empty string if s can not be written.
+func (f *class_formatter) format(s RuneSet, negate bool) string {
+	named, rest := f.pick_named(s)
+
+	if len(named) == 1 && len(rest) == 0 && named[0].escape {
+		if negate {
+			// \d -> \D, \p{Greek} -> \P{Greek}
+			name := named[0].name
+			return name[:1] + strings.ToUpper(name[1:2]) + name[2:]
+		}
+		return named[0].name
+	}
+	if len(named) == 0 && len(rest) == 0 && f.d != JavaScript {
+		// empty brackets are only valid in JavaScript
+		return ""
+	}
+
+	w := strings.Builder{}
+	w.WriteByte('[')
+	if negate {
+		if f.d == Glob {
+			w.WriteByte('!')
+		} else {
+			w.WriteByte('^')
+		}
+	}
+	if f.d == POSIX {
+		f.write_posix(&w, named, rest, negate)
+	} else {
+		for _, c := range named {
+			w.WriteString(c.name)
+		}
+		for lo, hi := range rest.Ranges() {
+			f.write_range(&w, lo, hi)
+		}
+	}
+	w.WriteByte(']')
+	return w.String()
+}
+
+// pick_named greedily selects the named classes that shorten the class for s.
+// Returns the selected classes and the remaining set that has to be written
+// as ranges.
+func (f *class_formatter) pick_named(s RuneSet) ([]named_class, RuneSet) {
+	var picked []named_class
+	rest := s
+	for len(rest) > 0 {
+		cost := f.cost(rest, -1)
+		best, best_gain := -1, 0
+		for i, c := range f.named {
+			limit := cost - len(c.name) - best_gain
+			if limit <= 0 || Disjoint(c.set, rest) || !IsSubset(c.set, s) {
+				continue
+			}
+			f.tmp = DifferenceInto(f.tmp, rest, c.set)
+			gain := cost - f.cost(f.tmp, limit) - len(c.name)
+			if gain > best_gain {
+				best, best_gain = i, gain
+			}
+		}
+		if best < 0 {
+			break
+		}
+		picked = append(picked, f.named[best])
+		rest = Difference(rest, f.named[best].set)
+	}
+	return picked, rest
+}
+
+// cost returns the length of s written as ranges. If limit is not negative,
+// the result is only exact up to the limit.
+func (f *class_formatter) cost(s RuneSet, limit int) int {
+	f.buf.Reset()
+	for lo, hi := range s.Ranges() {
+		if limit >= 0 && f.buf.Len() >= limit {
+			break
+		}
+		f.write_range(&f.buf, lo, hi)
+	}
+	return f.buf.Len()
+}
+
+func (f *class_formatter) write_range(w *strings.Builder, lo, hi rune) {
+	f.write_char(w, lo)
+	if hi > lo {
+		if hi > lo+1 {
+			w.WriteByte('-')
+		}
+		f.write_char(w, hi)
+	}
+}
+
+func (f *class_formatter) write_char(w *strings.Builder, r rune) {
+	switch f.d {
+	case POSIX:
+		w.WriteRune(r)
+		return
+	case Glob:
+		if strings.ContainsRune(`\[]-^!`, r) {
+			w.WriteByte('\\')
+		}
+		w.WriteRune(r)
+		return
+	}
+
+	switch {
+	case r == '\t':
+		w.WriteString(`\t`)
+	case r == '\n':
+		w.WriteString(`\n`)
+	case r == '\f':
+		w.WriteString(`\f`)
+	case r == '\r':
+		w.WriteString(`\r`)
+	case strings.ContainsRune(`\[]-^`, r) || (r == '&' && f.d == Java):
+		w.WriteByte('\\')
+		w.WriteByte(byte(r))
+	case unicode.IsPrint(r):
+		w.WriteRune(r)
+	case r < 0x100:
+		w.WriteString(`\x`)
+		write_hex(w, r, 2)
+	case f.d == JavaScript && r < 0x10000:
+		w.WriteString(`\u`)
+		write_hex(w, r, 4)
+	case f.d == JavaScript:
+		w.WriteString(`\u{`)
+		write_hex(w, r, 1)
+		w.WriteByte('}')
+	default:
+		w.WriteString(`\x{`)
+		write_hex(w, r, 1)
+		w.WriteByte('}')
+	}
+}
+
+// write_hex writes r as an uppercase hexadecimal number of at least n digits.
+func write_hex(w *strings.Builder, r rune, n int) {
+	for n < 8 && r>>(4*n) != 0 {
+		n++
+	}
+	for i := n - 1; i >= 0; i-- {
+		w.WriteByte(hex[(r>>(4*i))&0xf])
+	}
+}
+
+// write_posix writes the body of a POSIX bracket expression. Backslashes have
+// no special meaning in POSIX, so the characters that would be special at
+// their position are written separately, in the order that keeps them literal:
+// ']' goes first, '[' and '^' go after everything else, '-' goes last.
+func (f *class_formatter) write_posix(w *strings.Builder, named []named_class, s RuneSet, negate bool) {
+	const specials = "]-^["
+	var has [len(specials)]bool
+	take := func(r rune) bool {
+		if i := strings.IndexRune(specials, r); i >= 0 {
+			has[i] = true
+			return true
+		}
+		return false
+	}
+
+	body := strings.Builder{}
+	for _, c := range named {
+		body.WriteString(c.name)
+	}
+	for lo, hi := range s.Ranges() {
+		for lo <= hi && take(lo) {
+			lo++
+		}
+		for lo <= hi && take(hi) {
+			hi--
+		}
+		if lo <= hi {
+			f.write_range(&body, lo, hi)
+		}
+	}
+
+	if has[0] {
+		w.WriteByte(']')
+	}
+	w.WriteString(body.String())
+	if has[3] {
+		w.WriteByte('[')
+	}
+	if has[2] {
+		if !negate && w.Len() == 1 {
+			// a leading '^' would negate the expression
+			if has[1] {
+				w.WriteString("-^")
+				return
+			}
+			w.WriteString("[.^.]")
+			return
+		}
+		w.WriteByte('^')
+	}
+	if has[1] {
+		w.WriteByte('-')
+	}
+}
+
+var (
+	unicode_classes_once sync.Once
+	unicode_categories   []named_class
+	unicode_scripts      []named_class
+)
+
+// load_unicode_classes converts the tables of the unicode package into sets
+// named after the table names.
+func load_unicode_classes() {
+	load := func(tables map[string]*unicode.RangeTable) []named_class {
+		r := make([]named_class, 0, len(tables))
+		for name, t := range tables {
+			r = append(r, named_class{name: name, set: FromRangeTable(t)})
+		}
+		sort.Slice(r, func(i, j int) bool {
+			return r[i].name < r[j].name
+		})
+		return r
+	}
+	unicode_categories = load(unicode.Categories)
+	unicode_scripts = load(unicode.Scripts)
+}
+
+// named_classes returns the named classes supported by the dialect d.
+func named_classes(d Dialect) []named_class {
+	var r []named_class
+	add := func(name string, s RuneSet, escape bool) {
+		r = append(r, named_class{name, s, escape})
+	}
+
+	switch d {
+	case RE2, PCRE, JavaScript, Java:
+		add(`\d`, perl_classes['d'], true)
+		add(`\w`, perl_classes['w'], true)
+		if d == RE2 {
+			// the other dialects include \v in \s
+			add(`\s`, perl_classes['s'], true)
+		}
+	}
+
+	posix := make([]string, 0, len(posix_classes))
+	for name := range posix_classes {
+		posix = append(posix, name)
+	}
+	sort.Strings(posix)
+	switch d {
+	case RE2, PCRE:
+		for _, name := range posix {
+			add("[:"+name+":]", posix_classes[name], false)
+		}
+	case POSIX:
+		for _, name := range posix {
+			if name != "ascii" && name != "word" {
+				add("[:"+name+":]", posix_classes[name], false)
+			}
+		}
+	case Java:
+		for java, name := range java_posix_classes {
+			add(`\p{`+java+`}`, posix_classes[name], true)
+		}
+		sort.Slice(r, func(i, j int) bool {
+			return r[i].name < r[j].name
+		})
+	}
+
+	if d == POSIX || d == Glob {
+		return r
+	}
+
+	unicode_classes_once.Do(load_unicode_classes)
+	if d != Java {
+		add(`\p{Any}`, RuneSet{0}, true)
+	}
+	for _, c := range unicode_categories {
+		switch {
+		case c.name == "LC" && d == PCRE:
+			// PCRE spells it L&
+			add(`\p{L&}`, c.set, true)
+		case len(c.name) == 1 && d != JavaScript:
+			add(`\p`+c.name, c.set, true)
+		default:
+			add(`\p{`+c.name+`}`, c.set, true)
+		}
+	}
+	for _, c := range unicode_scripts {
+		switch d {
+		case JavaScript:
+			add(`\p{sc=`+c.name+`}`, c.set, true)
+		case Java:
+			add(`\p{Is`+c.name+`}`, c.set, true)
+		default:
+			add(`\p{`+c.name+`}`, c.set, true)
+		}
+	}
+	return r
+}
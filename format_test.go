@@ -0,0 +1,139 @@
+package ics
+
+import (
+	"math/rand"
+	"regexp"
+	"testing"
+	"unicode"
+)
+
+func TestRuneSet_FormatClass(t *testing.T) {
+	greek := FromRangeTable(unicode.Greek)
+	tests := []struct {
+		s    RuneSet
+		d    Dialect
+		want string
+	}{
+		{RuneSet{'a', 'z' + 1}, RE2, "[a-z]"},
+		{RuneSet{'a', 'z' + 1}, Plain, "a-z"},
+		{RuneSet{'a', 'z' + 1}.Inverted(), RE2, "[^a-z]"},
+		{RuneSet{'a', 'z' + 1}.Inverted(), Glob, "[!a-z]"},
+		{RuneSet{'0', '9' + 1}, RE2, `\d`},
+		{RuneSet{'0', '9' + 1}.Inverted(), PCRE, `\D`},
+		{RuneSet{'0', '9' + 1, 'a', 'a' + 1}, Java, `[\da]`},
+		{RuneSet{'0', '9' + 1}, POSIX, "[0-9]"},
+		{RuneSet{'A', 'Z' + 1, 'a', 'z' + 1}, POSIX, "[A-Za-z]"},
+		{posix_classes["punct"], POSIX, "[[:punct:]]"},
+		{posix_classes["punct"], Java, `\p{Punct}`},
+		{RuneSet{'\t', '\n' + 1, '\f', '\r' + 1, ' ', ' ' + 1}, RE2, `\s`},
+		{RuneSet{'\t', '\n' + 1, '\f', '\r' + 1, ' ', ' ' + 1}, PCRE, `[\t\n\f\r ]`},
+		{greek, RE2, `\p{Greek}`},
+		{greek.Inverted(), RE2, `\P{Greek}`},
+		{greek, JavaScript, `\p{sc=Greek}`},
+		{greek, Java, `\p{IsGreek}`},
+		{Union(greek, RuneSet{'_', '_' + 1}), RE2, `[\p{Greek}_]`},
+		{FromRangeTable(unicode.L), RE2, `\pL`},
+		{FromRangeTable(unicode.L), JavaScript, `\p{L}`},
+
+		{RuneSet{'-', '-' + 1, '\\', '^' + 1}, RE2, `[\-\\-\^]`},
+		{RuneSet{'&', '&' + 1}, Java, `[\&]`},
+		{RuneSet{'!', '!' + 1, '^', '^' + 1}, Glob, `[\!\^]`},
+		{RuneSet{'-', '-' + 1, ']', '^' + 1}, POSIX, "[]^-]"},
+		{RuneSet{'^', '^' + 1}, POSIX, "[[.^.]]"},
+		{RuneSet{'-', '-' + 1, '^', '^' + 1}, POSIX, "[-^]"},
+		{RuneSet{'[', '[' + 1, ':', ':' + 1}, POSIX, "[:[]"},
+
+		{RuneSet{0, 0x1f + 1, 0xad, 0xad + 1}, RE2, `[\x00-\x1F\xAD]`},
+		{RuneSet{0xe000, 0xe000 + 1}, RE2, `[\x{E000}]`},
+		{RuneSet{0xe000, 0xe000 + 1}, JavaScript, `[\uE000]`},
+		{RuneSet{0x10ffff}, JavaScript, `[\u{10FFFF}]`},
+		{RuneSet{'é', 'é' + 1}, PCRE, "[é]"},
+
+		{RuneSet{}, RE2, `\P{Any}`},
+		{RuneSet{}, JavaScript, "[]"},
+		{RuneSet{0}, JavaScript, "[^]"},
+		{RuneSet{}, Java, `[^\x00-\x{10FFFF}]`},
+		{RuneSet{0xd800, 0xdfff + 1}, POSIX, "[^\x00-퟿-\U0010ffff]"},
+	}
+	for _, tt := range tests {
+		if got := tt.s.FormatClass(tt.d); got != tt.want {
+			t.Errorf("%v.FormatClass(%d) = %q, want %q", tt.s, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestAsciiSet_FormatClass(t *testing.T) {
+	tests := []struct {
+		s    AsciiSet
+		d    Dialect
+		want string
+	}{
+		{AsciiSet{'a', 'z' + 1}, RE2, "[a-z]"},
+		{AsciiSet{'a', 'z' + 1}.Inverted(), RE2, "[\\x00-`{-\\x7F]"},
+		{AsciiSet{'0'}, Java, `[0-\x7F]`},
+		{AsciiSet{'-', '-' + 1}, Plain, `\-`},
+	}
+	for _, tt := range tests {
+		if got := tt.s.FormatClass(tt.d); got != tt.want {
+			t.Errorf("%v.FormatClass(%d) = %q, want %q", tt.s, tt.d, got, tt.want)
+		}
+	}
+}
+
+// random_runeset produces a set with boundaries clustered around characters
+// that are special in character classes.
+func random_runeset(n int) RuneSet {
+	pool := []rune{0, '\t', ' ', '!', '&', '-', '0', ':', '[', '\\', ']', '^', 'a', 0x7f, 0xe9, 0x3b1, 0xd800, 0xe000, 0x10000}
+	b := RuneSetBuilder{}
+	for i := 0; i < n; i++ {
+		lo := pool[rand.Intn(len(pool))] + rune(rand.Intn(4))
+		b.AddRange(lo, lo+rune(rand.Intn(4)))
+	}
+	if rand.Intn(4) == 0 {
+		b.Add(unicode.MaxRune)
+	}
+	return b.Build()
+}
+
+func TestRuneSet_FormatClass_RoundTrip(t *testing.T) {
+	surrogates := RuneSet{0xd800, 0xdfff + 1}
+	for i := 0; i < 200; i++ {
+		s := random_runeset(rand.Intn(8))
+		for _, d := range []Dialect{Plain, RE2, PCRE, JavaScript, Java, POSIX, Glob} {
+			src := s.FormatClass(d)
+			got, err := ParseRuneSet(src, d)
+			if err != nil {
+				t.Fatalf("%v.FormatClass(%d) = %q: %v", s, d, src, err)
+			}
+			want := s
+			if d == POSIX || d == Glob {
+				want = Difference(s, surrogates)
+				got = Difference(got, surrogates)
+			}
+			if !got.Equal(want) {
+				t.Fatalf("%v.FormatClass(%d) = %q, parses as %v", s, d, src, got)
+			}
+		}
+	}
+}
+
+func TestRuneSet_FormatClass_Regexp(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		s := random_runeset(rand.Intn(8))
+		src := s.FormatClass(RE2)
+		re, err := regexp.Compile(`^` + src + `$`)
+		if err != nil {
+			t.Fatalf("%v.FormatClass(RE2) = %q: %v", s, src, err)
+		}
+		for _, r := range s {
+			for _, r := range []rune{r - 1, r, r + 1} {
+				if r < 0 || r > unicode.MaxRune || (r >= 0xd800 && r <= 0xdfff) {
+					continue
+				}
+				if re.MatchString(string(r)) != s.Contains(r) {
+					t.Fatalf("%q mismatches %v at %U", src, s, r)
+				}
+			}
+		}
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/adnsv/ics
 
-go 1.19
+go 1.23
 
 require golang.org/x/exp v0.0.0-20221106115401-f9659909a136
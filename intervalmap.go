@@ -0,0 +1,166 @@
+package ics
+
+import (
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// IntervalMap associates values with arithmetic intervals. It is a multi-class
+// generalization of Set: instead of testing containment, a lookup returns the
+// value that is associated with the interval a key falls into.
+//
+// Internally, the map is stored as a sorted array of boundaries. Each boundary
+// starts a segment that extends up to the next boundary, the last segment is
+// open-ended. A segment is either associated with a value or it is a gap.
+// Adjacent segments with equal values are coalesced automatically, so the
+// representation stays minimal.
+//
+// The zero value is an empty map ready to use.
+type IntervalMap[K constraints.Ordered, V comparable] struct {
+	bounds []K
+	vals   []V
+	has    []bool
+}
+
+// Get returns the value associated with k.
+func (m *IntervalMap[K, V]) Get(k K) (v V, ok bool) {
+	i, be := search(m.bounds, k)
+	if !be {
+		i--
+	}
+	if i < 0 || !m.has[i] {
+		return
+	}
+	return m.vals[i], true
+}
+
+// Set associates v with an interval, overwriting the values previously
+// associated with it.
+//
+//   - if l < h, a bounded interval [l,h) is assigned
+//   - if l >= h, a half-open interval [l,... is assigned instead
+func (m *IntervalMap[K, V]) Set(l, h K, v V) {
+	m.update(l, h, func(V, bool) (V, bool) {
+		return v, true
+	})
+}
+
+// Merge associates v with an interval, combining it with the values that were
+// previously associated with it. The parts of the interval that had no values
+// are assigned v as is. The interval bounds follow the same rules as in Set.
+func (m *IntervalMap[K, V]) Merge(l, h K, v V, combine func(old, v V) V) {
+	m.update(l, h, func(old V, ok bool) (V, bool) {
+		if ok {
+			return combine(old, v), true
+		}
+		return v, true
+	})
+}
+
+// Delete removes the values associated with an interval. The interval bounds
+// follow the same rules as in Set.
+func (m *IntervalMap[K, V]) Delete(l, h K) {
+	m.update(l, h, func(V, bool) (v V, ok bool) {
+		return
+	})
+}
+
+// Enumerate is a functional enumerator for all the intervals that have values
+// associated with them. The callback is called with half-open boundaries for
+// each interval. For the open-ended interval, the callback is called with
+// l = h.
+func (m *IntervalMap[K, V]) Enumerate(f func(l, h K, v V)) {
+	n := len(m.bounds)
+	for i := 0; i < n; i++ {
+		if !m.has[i] {
+			continue
+		}
+		if i+1 < n {
+			f(m.bounds[i], m.bounds[i+1], m.vals[i])
+		} else {
+			f(m.bounds[i], m.bounds[i], m.vals[i])
+		}
+	}
+}
+
+// Len returns the number of intervals that have values associated with them.
+func (m *IntervalMap[K, V]) Len() int {
+	r := 0
+	for _, ok := range m.has {
+		if ok {
+			r++
+		}
+	}
+	return r
+}
+
+// Defined returns a set of keys that have values associated with them.
+func (m *IntervalMap[K, V]) Defined() Set[K] {
+	// segments alternate between values and gaps, with the exception of
+	// adjacent segments that have different values
+	s := make(Set[K], 0, len(m.bounds))
+	in := false
+	for i, k := range m.bounds {
+		if m.has[i] != in {
+			in = !in
+			s = append(s, k)
+		}
+	}
+	return s
+}
+
+// update applies f to the segments within an interval.
+func (m *IntervalMap[K, V]) update(l, h K, f func(v V, ok bool) (V, bool)) {
+	li := m.split(l)
+	hi := len(m.bounds)
+	if l < h {
+		hi = m.split(h)
+	}
+	for i := li; i < hi; i++ {
+		v, ok := f(m.vals[i], m.has[i])
+		if !ok {
+			var zero V
+			v = zero
+		}
+		m.vals[i], m.has[i] = v, ok
+	}
+	m.coalesce()
+}
+
+// split ensures that there is a segment boundary at k and returns its index.
+func (m *IntervalMap[K, V]) split(k K) int {
+	i, be := search(m.bounds, k)
+	if be {
+		return i
+	}
+	var v V
+	var ok bool
+	if i > 0 {
+		v, ok = m.vals[i-1], m.has[i-1]
+	}
+	m.bounds = slices.Insert(m.bounds, i, k)
+	m.vals = slices.Insert(m.vals, i, v)
+	m.has = slices.Insert(m.has, i, ok)
+	return i
+}
+
+// coalesce drops leading gaps and merges adjacent segments with equal values.
+func (m *IntervalMap[K, V]) coalesce() {
+	j := 0
+	for i := range m.bounds {
+		if j == 0 {
+			if !m.has[i] {
+				continue
+			}
+		} else if m.has[i] == m.has[j-1] && m.vals[i] == m.vals[j-1] {
+			continue
+		}
+		m.bounds[j], m.vals[j], m.has[j] = m.bounds[i], m.vals[i], m.has[i]
+		j++
+	}
+	var zero V
+	for i := j; i < len(m.vals); i++ {
+		m.vals[i] = zero
+	}
+	m.bounds, m.vals, m.has = m.bounds[:j], m.vals[:j], m.has[:j]
+}
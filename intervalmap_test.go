@@ -0,0 +1,93 @@
+package ics
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestIntervalMap(t *testing.T) {
+	type slot struct {
+		v  int
+		ok bool
+	}
+	for iter := 0; iter < 300; iter++ {
+		var m IntervalMap[byte, int]
+		var ref [256]slot
+
+		for op := 0; op < 8; op++ {
+			l, h := byte(rand.Intn(256)), byte(rand.Intn(256))
+			v := rand.Intn(3)
+			in := func(k int) bool {
+				return k >= int(l) && (h <= l || k < int(h))
+			}
+			switch rand.Intn(3) {
+			case 0:
+				m.Set(l, h, v)
+				for k := range ref {
+					if in(k) {
+						ref[k] = slot{v, true}
+					}
+				}
+			case 1:
+				m.Merge(l, h, v, func(old, v int) int { return old + v })
+				for k := range ref {
+					if in(k) {
+						ref[k] = slot{ref[k].v + v, true}
+					}
+				}
+			case 2:
+				m.Delete(l, h)
+				for k := range ref {
+					if in(k) {
+						ref[k] = slot{}
+					}
+				}
+			}
+
+			for k := range ref {
+				v, ok := m.Get(byte(k))
+				if ok != ref[k].ok || v != ref[k].v {
+					t.Fatalf("Get(%d) = %v, %v, want %v, %v", k, v, ok, ref[k].v, ref[k].ok)
+				}
+			}
+			for i := 1; i < len(m.bounds); i++ {
+				if m.has[i] == m.has[i-1] && m.vals[i] == m.vals[i-1] {
+					t.Fatalf("segments %d and %d are not coalesced", i-1, i)
+				}
+			}
+			defined := m.Defined()
+			for k := range ref {
+				if defined.Contains(byte(k)) != ref[k].ok {
+					t.Fatalf("Defined() = %v, containment of %d should be %v", defined, k, ref[k].ok)
+				}
+			}
+		}
+	}
+}
+
+func TestIntervalMap_Enumerate(t *testing.T) {
+	var m IntervalMap[float64, string]
+	m.Set(0, 10, "low")
+	m.Set(10, 20, "low")
+	m.Set(30, 30, "high")
+	m.Merge(5, 35, "mid", func(old, v string) string { return old + "+" + v })
+	m.Delete(12, 14)
+
+	w := strings.Builder{}
+	m.Enumerate(func(l, h float64, v string) {
+		if l < h {
+			fmt.Fprintf(&w, "[%v,%v)=%s", l, h, v)
+		} else {
+			fmt.Fprintf(&w, "[%v...=%s", l, v)
+		}
+	})
+	want := "[0,5)=low[5,12)=low+mid[14,20)=low+mid[20,30)=mid[30,35)=high+mid[35...=high"
+	if got := w.String(); got != want {
+		t.Errorf("Enumerate() = %s, want %s", got, want)
+	}
+	if got := m.Len(); got != 6 {
+		t.Errorf("Len() = %d, want 6", got)
+	}
+}
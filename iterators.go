@@ -0,0 +1,117 @@
+package ics
+
+import (
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Intervals returns an iterator over the half-open boundaries of the intervals
+// within s. Similarly to Enumerate, the open-ended interval, if any, is
+// yielded with l = h.
+func Intervals[S ~[]T, T any](s S) iter.Seq2[T, T] {
+	return func(yield func(l, h T) bool) {
+		i, n := 0, len(s)
+		for i+1 < n {
+			if !yield(s[i], s[i+1]) {
+				return
+			}
+			i += 2
+		}
+		if i < n {
+			yield(s[i], s[i])
+		}
+	}
+}
+
+// Gaps returns an iterator over the half-open [l,h) holes between adjacent
+// intervals within s. The space below the first interval and above the last
+// one is not reported.
+func Gaps[S ~[]T, T any](s S) iter.Seq2[T, T] {
+	return func(yield func(l, h T) bool) {
+		for i := 1; i+1 < len(s); i += 2 {
+			if !yield(s[i], s[i+1]) {
+				return
+			}
+		}
+	}
+}
+
+// Elements returns an iterator over all the values contained within s. The
+// open-ended interval, if any, extends up to the largest value of T, same as
+// with Rank and Select.
+func Elements[S ~[]T, T constraints.Integer](s S) iter.Seq[T] {
+	return domain_elements(FullDomain[T](), s)
+}
+
+func domain_ranges[S ~[]T, T constraints.Integer](d Domain[T], s S) iter.Seq2[T, T] {
+	return func(yield func(lo, hi T) bool) {
+		i, n := 0, len(s)
+		for i+1 < n {
+			if !yield(s[i], s[i+1]-1) {
+				return
+			}
+			i += 2
+		}
+		if i < n {
+			yield(s[i], d.Max)
+		}
+	}
+}
+
+func domain_elements[S ~[]T, T constraints.Integer](d Domain[T], s S) iter.Seq[T] {
+	return func(yield func(v T) bool) {
+		for lo, hi := range domain_ranges(d, s) {
+			for v := lo; ; v++ {
+				if !yield(v) {
+					return
+				}
+				if v == hi {
+					// hi may be the maximum value of T
+					break
+				}
+			}
+		}
+	}
+}
+
+// Intervals returns an iterator over the half-open boundaries of the intervals
+// within s. See Intervals for details.
+func (s Set[T]) Intervals() iter.Seq2[T, T] {
+	return Intervals(s)
+}
+
+// Ranges returns an iterator over all the continuous inclusive [rmin,rmax]
+// ranges contained within the set.
+func (s RuneSet) Ranges() iter.Seq2[rune, rune] {
+	return domain_ranges(RuneDomain, s)
+}
+
+// Elements returns an iterator over all the codepoints contained within the
+// set.
+func (s RuneSet) Elements() iter.Seq[rune] {
+	return domain_elements(RuneDomain, s)
+}
+
+// Ranges returns an iterator over all the continuous inclusive [cmin,cmax]
+// ranges contained within the set.
+func (s AsciiSet) Ranges() iter.Seq2[byte, byte] {
+	return domain_ranges(AsciiDomain, s)
+}
+
+// Elements returns an iterator over all the characters contained within the
+// set.
+func (s AsciiSet) Elements() iter.Seq[byte] {
+	return domain_elements(AsciiDomain, s)
+}
+
+// Ranges returns an iterator over all the continuous inclusive [min,max]
+// ranges contained within the set.
+func (s DomainSet[T]) Ranges() iter.Seq2[T, T] {
+	return domain_ranges(s.Domain, s.Set)
+}
+
+// Elements returns an iterator over all the values contained within the set.
+func (s DomainSet[T]) Elements() iter.Seq[T] {
+	return domain_elements(s.Domain, s.Set)
+}
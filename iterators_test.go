@@ -0,0 +1,119 @@
+package ics
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestIntervals(t *testing.T) {
+	s := byteset{1, 3, 5, 8, 10}
+
+	var got [][2]byte
+	for l, h := range Intervals(s) {
+		got = append(got, [2]byte{l, h})
+	}
+	want := [][2]byte{{1, 3}, {5, 8}, {10, 10}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Intervals(%v) = %v, want %v", s, got, want)
+	}
+
+	got = got[:0]
+	for l, h := range Intervals(s) {
+		got = append(got, [2]byte{l, h})
+		if l == 5 {
+			break
+		}
+	}
+	want = [][2]byte{{1, 3}, {5, 8}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Intervals(%v) with break = %v, want %v", s, got, want)
+	}
+
+	got = got[:0]
+	for l, h := range Gaps(s) {
+		got = append(got, [2]byte{l, h})
+	}
+	want = [][2]byte{{3, 5}, {8, 10}}
+	if !slices.Equal(got, want) {
+		t.Errorf("Gaps(%v) = %v, want %v", s, got, want)
+	}
+}
+
+func TestRuneSet_Ranges(t *testing.T) {
+	s := RuneSet{'a', 'c', 0x10fffe}
+
+	var got [][2]rune
+	for rmin, rmax := range s.Ranges() {
+		got = append(got, [2]rune{rmin, rmax})
+	}
+	want := [][2]rune{{'a', 'b'}, {0x10fffe, 0x10ffff}}
+	if !slices.Equal(got, want) {
+		t.Errorf("RuneSet[%v].Ranges() = %v, want %v", s, got, want)
+	}
+
+	var elements []rune
+	for r := range s.Elements() {
+		elements = append(elements, r)
+	}
+	if want := []rune{'a', 'b', 0x10fffe, 0x10ffff}; !slices.Equal(elements, want) {
+		t.Errorf("RuneSet[%v].Elements() = %v, want %v", s, elements, want)
+	}
+}
+
+func TestAsciiSet_Elements(t *testing.T) {
+	s := AsciiSet{'0', '3', 0x7e}
+
+	var got []byte
+	for c := range s.Elements() {
+		got = append(got, c)
+		if c == 0x7e {
+			break
+		}
+	}
+	if want := []byte{'0', '1', '2', 0x7e}; !slices.Equal(got, want) {
+		t.Errorf("AsciiSet[%v].Elements() = %v, want %v", s, got, want)
+	}
+}
+
+func TestDomainSet_Elements(t *testing.T) {
+	s := DomainSet[int8]{Int8Domain, Set[int8]{math.MinInt8, math.MinInt8 + 2, math.MaxInt8 - 1}}
+
+	var got []int8
+	for v := range s.Elements() {
+		got = append(got, v)
+	}
+	if want := []int8{math.MinInt8, math.MinInt8 + 1, math.MaxInt8 - 1, math.MaxInt8}; !slices.Equal(got, want) {
+		t.Errorf("DomainSet[%v].Elements() = %v, want %v", s.Set, got, want)
+	}
+}
+
+func TestElements(t *testing.T) {
+	tests := []struct {
+		s    Set[int8]
+		want []int8
+	}{
+		{Set[int8]{}, nil},
+		{Set[int8]{-3, 0, 5, 7}, []int8{-3, -2, -1, 5, 6}},
+		{Set[int8]{120}, []int8{120, 121, 122, 123, 124, 125, 126, 127}},
+		{Set[int8]{-128, -126, 126}, []int8{-128, -127, 126, 127}},
+	}
+	for _, tt := range tests {
+		var got []int8
+		for v := range Elements(tt.s) {
+			got = append(got, v)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Elements(%v) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+
+	var got []uint
+	for v := range Elements([]uint{^uint(0) - 1}) {
+		got = append(got, v)
+	}
+	if want := []uint{^uint(0) - 1, ^uint(0)}; !slices.Equal(got, want) {
+		t.Errorf("Elements([max-1]) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,101 @@
+package ics
+
+import (
+	"unicode/utf8"
+)
+
+// RuneMatcher is an immutable compiled form of RuneSet with constant-time
+// lookups.
+//
+// The matcher is a three-stage trie: the top bits of a rune select a block of
+// the second stage, the middle bits select a leaf within the block, and the
+// low 6 bits select a bit within the 64-bit leaf. Identical blocks and leaves
+// are shared, so sets that consist of a few long ranges compile into small
+// tables, while fragmented sets, such as \p{L}, take at most a few kilobytes.
+type RuneMatcher struct {
+	stage1 []uint16 // block index for r>>11
+	stage2 []uint16 // leaf index for (r>>6)&31, 32 entries per block
+	leaves []uint64 // bits for r&63
+}
+
+// Compile produces a matcher for s.
+func (s RuneSet) Compile() *RuneMatcher {
+	const (
+		leaf_bits  = 6
+		block_bits = 5
+		block_size = 1 << block_bits
+	)
+
+	words := make([]uint64, (utf8.MaxRune+1)>>leaf_bits)
+	s.EnumerateRanges(func(rmin, rmax rune) {
+		for w := rmin >> leaf_bits; w <= rmax>>leaf_bits; w++ {
+			mask := ^uint64(0)
+			if lo := w << leaf_bits; rmin > lo {
+				mask <<= rmin - lo
+			}
+			if hi := w<<leaf_bits + 63; rmax < hi {
+				mask &^= ^uint64(0) << (rmax - hi + 64)
+			}
+			words[w] |= mask
+		}
+	})
+
+	m := &RuneMatcher{}
+	leaf_index := map[uint64]uint16{}
+	block_index := map[[block_size]uint16]uint16{}
+	leaf := func(w uint64) uint16 {
+		i, ok := leaf_index[w]
+		if !ok {
+			i = uint16(len(m.leaves))
+			leaf_index[w] = i
+			m.leaves = append(m.leaves, w)
+		}
+		return i
+	}
+	block := func(b [block_size]uint16) uint16 {
+		i, ok := block_index[b]
+		if !ok {
+			i = uint16(len(m.stage2) / block_size)
+			block_index[b] = i
+			m.stage2 = append(m.stage2, b[:]...)
+		}
+		return i
+	}
+
+	// the empty leaf and the empty block go first, so that trailing empty
+	// blocks can be trimmed from the first stage
+	leaf(0)
+	block([block_size]uint16{})
+
+	for i := 0; i < len(words); i += block_size {
+		b := [block_size]uint16{}
+		for j := range b {
+			b[j] = leaf(words[i+j])
+		}
+		m.stage1 = append(m.stage1, block(b))
+	}
+	n := len(m.stage1)
+	for n > 0 && m.stage1[n-1] == 0 {
+		n--
+	}
+	m.stage1 = m.stage1[:n:n]
+	return m
+}
+
+// Contains indicates if r is contained within m.
+func (m *RuneMatcher) Contains(r rune) bool {
+	i := uint32(r) >> 11
+	if i >= uint32(len(m.stage1)) {
+		return false
+	}
+	b := uint32(m.stage1[i])<<5 | uint32(r>>6)&31
+	return m.leaves[m.stage2[b]]>>(r&63)&1 != 0
+}
+
+// Footprint returns the number of bytes occupied by the tables of m. For
+// comparison, the flattened RuneSet s occupies 4*len(s) bytes.
+func (m *RuneMatcher) Footprint() int {
+	return 2*len(m.stage1) +
+		2*len(m.stage2) +
+		8*len(m.leaves)
+}
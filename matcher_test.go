@@ -0,0 +1,76 @@
+package ics
+
+import (
+	"math/rand"
+	"testing"
+	"unicode"
+)
+
+func TestRuneSet_Compile(t *testing.T) {
+	sets := []RuneSet{
+		{},
+		{0},
+		{'a', 'z' + 1},
+		{0x3f, 0x41, 0x7f, 0x80, 0x10fffe},
+		FromRangeTable(unicode.L),
+		FromRangeTable(unicode.Han),
+		FromRangeTable(unicode.L).Inverted(),
+	}
+	for i := 0; i < 20; i++ {
+		sets = append(sets, random_runeset(rand.Intn(16)))
+	}
+	for _, s := range sets {
+		m := s.Compile()
+		check := func(r rune) {
+			if m.Contains(r) != s.Contains(r) {
+				t.Fatalf("%v.Compile().Contains(%U) = %v", s, r, m.Contains(r))
+			}
+		}
+		for _, r := range s {
+			for d := rune(-65); d <= 65; d++ {
+				check(r + d)
+			}
+		}
+		for i := 0; i < 1000; i++ {
+			check(rand.Int31n(unicode.MaxRune + 1))
+		}
+		check(-1)
+		check(unicode.MaxRune)
+		check(unicode.MaxRune + 1)
+	}
+}
+
+func TestRuneMatcher_Footprint(t *testing.T) {
+	tests := []struct {
+		s   RuneSet
+		max int
+	}{
+		{RuneSet{}, 2*32 + 8},
+		{RuneSet{0}, 2*544 + 4*32 + 2*8},
+		{FromRangeTable(unicode.L), 16 << 10},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Compile().Footprint(); got > tt.max {
+			t.Errorf("%v.Compile().Footprint() = %d, want at most %d", tt.s, got, tt.max)
+		}
+	}
+}
+
+func BenchmarkRuneMatcher_Contains(b *testing.B) {
+	s := FromRangeTable(unicode.L)
+	runes := make([]rune, 1024)
+	for i := range runes {
+		runes[i] = rand.Int31n(0x30000)
+	}
+	b.Run("RuneSet", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s.Contains(runes[i&1023])
+		}
+	})
+	b.Run("RuneMatcher", func(b *testing.B) {
+		m := s.Compile()
+		for i := 0; i < b.N; i++ {
+			m.Contains(runes[i&1023])
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package ics
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// count_le returns the number of boundaries of s that are less than or equal
+// to x. The count is odd if and only if x is contained in s, in which case
+// s[count-1] is the lower bound of the interval that contains x.
+func count_le[S ~[]T, T constraints.Ordered](s S, x T) int {
+	i, ok := search(s, x)
+	if ok {
+		i++
+	}
+	return i
+}
+
+// NextIn returns the smallest element of s that is greater than or equal to
+// x. Returns false if there is none.
+func NextIn[S ~[]T, T constraints.Ordered](s S, x T) (T, bool) {
+	k := count_le(s, x)
+	switch {
+	case k&1 == 1:
+		return x, true
+	case k < len(s):
+		return s[k], true
+	}
+	return x, false
+}
+
+// NextNotIn returns the smallest value that is greater than or equal to x and
+// is not contained in s. Returns false if x is within an open-ended interval.
+func NextNotIn[S ~[]T, T constraints.Ordered](s S, x T) (T, bool) {
+	k := count_le(s, x)
+	switch {
+	case k&1 == 0:
+		return x, true
+	case k < len(s):
+		return s[k], true
+	}
+	return x, false
+}
+
+// PrevIn returns the largest element of s that is less than or equal to x.
+// Returns false if there is none.
+//
+// Unlike NextIn, PrevIn is only available for integers: the intervals are
+// half-open, so the largest element below an upper boundary exists only for
+// discrete values.
+func PrevIn[S ~[]T, T constraints.Integer](s S, x T) (T, bool) {
+	k := count_le(s, x)
+	switch {
+	case k&1 == 1:
+		return x, true
+	case k > 0:
+		return s[k-1] - 1, true
+	}
+	return x, false
+}
+
+// PrevNotIn returns the largest value that is less than or equal to x and is
+// not contained in s. Returns false if there is none, which happens when x is
+// within an interval that starts at the smallest value of T.
+func PrevNotIn[S ~[]T, T constraints.Integer](s S, x T) (T, bool) {
+	k := count_le(s, x)
+	if k&1 == 0 {
+		return x, true
+	}
+	if l := s[k-1]; l-1 < l {
+		return l - 1, true
+	}
+	return x, false
+}
+
+// IntervalAt returns the half-open interval [l,h) of s that contains x. For an
+// open-ended interval [l,..., open is true and h is set to l. Returns ok =
+// false if x is not contained in s.
+func IntervalAt[S ~[]T, T constraints.Ordered](s S, x T) (l, h T, open, ok bool) {
+	k := count_le(s, x)
+	switch {
+	case k&1 == 0:
+		return x, x, false, false
+	case k < len(s):
+		return s[k-1], s[k], false, true
+	}
+	return s[k-1], s[k-1], true, true
+}
+
+// NextIn returns the smallest element of s that is greater than or equal to
+// x. See NextIn for details.
+func (s Set[T]) NextIn(x T) (T, bool) {
+	return NextIn(s, x)
+}
+
+// NextNotIn returns the smallest value that is greater than or equal to x and
+// is not contained in s. See NextNotIn for details.
+func (s Set[T]) NextNotIn(x T) (T, bool) {
+	return NextNotIn(s, x)
+}
+
+// IntervalAt returns the interval of s that contains x. See IntervalAt for
+// details.
+func (s Set[T]) IntervalAt(x T) (l, h T, open, ok bool) {
+	return IntervalAt(s, x)
+}
+
+func domain_next_in[S ~[]T, T constraints.Integer](d Domain[T], s S, x T) (T, bool) {
+	if !d.Contains(x) {
+		return x, false
+	}
+	return NextIn(s, x)
+}
+
+func domain_next_not_in[S ~[]T, T constraints.Integer](d Domain[T], s S, x T) (T, bool) {
+	if !d.Contains(x) {
+		return x, false
+	}
+	return NextNotIn(s, x)
+}
+
+func domain_prev_in[S ~[]T, T constraints.Integer](d Domain[T], s S, x T) (T, bool) {
+	if !d.Contains(x) {
+		return x, false
+	}
+	return PrevIn(s, x)
+}
+
+func domain_prev_not_in[S ~[]T, T constraints.Integer](d Domain[T], s S, x T) (T, bool) {
+	if !d.Contains(x) {
+		return x, false
+	}
+	v, ok := PrevNotIn(s, x)
+	if !ok || v < d.Min {
+		return x, false
+	}
+	return v, true
+}
+
+// domain_range_at returns the inclusive [lo,hi] range of s that contains x.
+func domain_range_at[S ~[]T, T constraints.Integer](d Domain[T], s S, x T) (lo, hi T, ok bool) {
+	if !d.Contains(x) {
+		return x, x, false
+	}
+	l, h, open, ok := IntervalAt(s, x)
+	switch {
+	case !ok:
+		return x, x, false
+	case open:
+		return l, d.Max, true
+	}
+	return l, h - 1, true
+}
+
+// NextIn returns the smallest rune of s that is greater than or equal to r.
+// Returns false if there is none.
+func (s RuneSet) NextIn(r rune) (rune, bool) {
+	return domain_next_in(RuneDomain, s, r)
+}
+
+// NextNotIn returns the smallest rune that is greater than or equal to r and
+// is not contained in s. Returns false if there is none.
+func (s RuneSet) NextNotIn(r rune) (rune, bool) {
+	return domain_next_not_in(RuneDomain, s, r)
+}
+
+// PrevIn returns the largest rune of s that is less than or equal to r.
+// Returns false if there is none.
+func (s RuneSet) PrevIn(r rune) (rune, bool) {
+	return domain_prev_in(RuneDomain, s, r)
+}
+
+// PrevNotIn returns the largest rune that is less than or equal to r and is
+// not contained in s. Returns false if there is none.
+func (s RuneSet) PrevNotIn(r rune) (rune, bool) {
+	return domain_prev_not_in(RuneDomain, s, r)
+}
+
+// RangeAt returns the inclusive [rmin,rmax] range of s that contains r.
+// Returns false if r is not contained in s.
+func (s RuneSet) RangeAt(r rune) (rmin, rmax rune, ok bool) {
+	return domain_range_at(RuneDomain, s, r)
+}
+
+// NextIn returns the smallest character of s that is greater than or equal to
+// c. Returns false if there is none.
+func (s AsciiSet) NextIn(c byte) (byte, bool) {
+	return domain_next_in(AsciiDomain, s, c)
+}
+
+// NextNotIn returns the smallest ascii character that is greater than or
+// equal to c and is not contained in s. Returns false if there is none.
+func (s AsciiSet) NextNotIn(c byte) (byte, bool) {
+	return domain_next_not_in(AsciiDomain, s, c)
+}
+
+// PrevIn returns the largest character of s that is less than or equal to c.
+// Returns false if there is none.
+func (s AsciiSet) PrevIn(c byte) (byte, bool) {
+	return domain_prev_in(AsciiDomain, s, c)
+}
+
+// PrevNotIn returns the largest ascii character that is less than or equal to
+// c and is not contained in s. Returns false if there is none.
+func (s AsciiSet) PrevNotIn(c byte) (byte, bool) {
+	return domain_prev_not_in(AsciiDomain, s, c)
+}
+
+// RangeAt returns the inclusive [cmin,cmax] range of s that contains c.
+// Returns false if c is not contained in s.
+func (s AsciiSet) RangeAt(c byte) (cmin, cmax byte, ok bool) {
+	return domain_range_at(AsciiDomain, s, c)
+}
+
+// NextIn returns the smallest value of s that is greater than or equal to v.
+// Returns false if there is none.
+func (s DomainSet[T]) NextIn(v T) (T, bool) {
+	return domain_next_in(s.Domain, s.Set, v)
+}
+
+// NextNotIn returns the smallest value of the domain that is greater than or
+// equal to v and is not contained in s. Returns false if there is none.
+func (s DomainSet[T]) NextNotIn(v T) (T, bool) {
+	return domain_next_not_in(s.Domain, s.Set, v)
+}
+
+// PrevIn returns the largest value of s that is less than or equal to v.
+// Returns false if there is none.
+func (s DomainSet[T]) PrevIn(v T) (T, bool) {
+	return domain_prev_in(s.Domain, s.Set, v)
+}
+
+// PrevNotIn returns the largest value of the domain that is less than or
+// equal to v and is not contained in s. Returns false if there is none.
+func (s DomainSet[T]) PrevNotIn(v T) (T, bool) {
+	return domain_prev_not_in(s.Domain, s.Set, v)
+}
+
+// RangeAt returns the inclusive [min,max] range of s that contains v. Returns
+// false if v is not contained in s.
+func (s DomainSet[T]) RangeAt(v T) (min, max T, ok bool) {
+	return domain_range_at(s.Domain, s.Set, v)
+}
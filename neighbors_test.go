@@ -0,0 +1,137 @@
+package ics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"unicode"
+)
+
+func TestNeighbors_Random(t *testing.T) {
+	type result struct {
+		v  int8
+		ok bool
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		s := random_int8set(r)
+
+		for x := math.MinInt8; x <= math.MaxInt8; x++ {
+			x := int8(x)
+			want_next, want_next_not := result{x, false}, result{x, false}
+			for v := int(x); v <= math.MaxInt8; v++ {
+				if !want_next.ok && s.Contains(int8(v)) {
+					want_next = result{int8(v), true}
+				}
+				if !want_next_not.ok && !s.Contains(int8(v)) {
+					want_next_not = result{int8(v), true}
+				}
+			}
+			want_prev, want_prev_not := result{x, false}, result{x, false}
+			for v := int(x); v >= math.MinInt8; v-- {
+				if !want_prev.ok && s.Contains(int8(v)) {
+					want_prev = result{int8(v), true}
+				}
+				if !want_prev_not.ok && !s.Contains(int8(v)) {
+					want_prev_not = result{int8(v), true}
+				}
+			}
+
+			if v, ok := NextIn(s, x); (result{v, ok}) != want_next {
+				t.Fatalf("NextIn(%v, %d) = %d, %v, want %v", s, x, v, ok, want_next)
+			}
+			if v, ok := NextNotIn(s, x); (result{v, ok}) != want_next_not {
+				t.Fatalf("NextNotIn(%v, %d) = %d, %v, want %v", s, x, v, ok, want_next_not)
+			}
+			if v, ok := PrevIn(s, x); (result{v, ok}) != want_prev {
+				t.Fatalf("PrevIn(%v, %d) = %d, %v, want %v", s, x, v, ok, want_prev)
+			}
+			if v, ok := PrevNotIn(s, x); (result{v, ok}) != want_prev_not {
+				t.Fatalf("PrevNotIn(%v, %d) = %d, %v, want %v", s, x, v, ok, want_prev_not)
+			}
+
+			l, h, open, ok := IntervalAt(s, x)
+			if ok != s.Contains(x) {
+				t.Fatalf("IntervalAt(%v, %d) = %v, want %v", s, x, ok, !ok)
+			}
+			if ok && (!s.ContainsInterval(l, h) || s.Contains(l-1) && l > math.MinInt8 || !open && s.Contains(h) || open != (h == l) || l > x || !open && h <= x) {
+				t.Fatalf("IntervalAt(%v, %d) = [%d,%d), %v", s, x, l, h, open)
+			}
+		}
+	}
+}
+
+func TestSet_NextIn(t *testing.T) {
+	s := Set[float64]{0.5, 1.5, 3}
+	tests := []struct {
+		x, next, next_not float64
+		ok_next, ok_not   bool
+	}{
+		{-1, 0.5, -1, true, true},
+		{1, 1, 1.5, true, true},
+		{1.5, 3, 1.5, true, true},
+		{4, 4, 4, true, false},
+	}
+	for _, tt := range tests {
+		if v, ok := s.NextIn(tt.x); v != tt.next || ok != tt.ok_next {
+			t.Errorf("%v.NextIn(%v) = %v, %v", s, tt.x, v, ok)
+		}
+		if v, ok := s.NextNotIn(tt.x); v != tt.next_not || ok != tt.ok_not {
+			t.Errorf("%v.NextNotIn(%v) = %v, %v", s, tt.x, v, ok)
+		}
+	}
+	if l, h, open, ok := s.IntervalAt(1); l != 0.5 || h != 1.5 || open || !ok {
+		t.Errorf("%v.IntervalAt(1) = %v, %v, %v, %v", s, l, h, open, ok)
+	}
+	if l, h, open, ok := s.IntervalAt(1e9); l != 3 || h != 3 || !open || !ok {
+		t.Errorf("%v.IntervalAt(1e9) = %v, %v, %v, %v", s, l, h, open, ok)
+	}
+}
+
+func TestRuneSet_RangeAt(t *testing.T) {
+	s := RuneSet{0, 'a', 0x10000}
+	tests := []struct {
+		r          rune
+		rmin, rmax rune
+		ok         bool
+	}{
+		{0, 0, 'a' - 1, true},
+		{'m', 0, 0, false},
+		{0x10ffff, 0x10000, unicode.MaxRune, true},
+		{0x110000, 0, 0, false},
+		{-1, 0, 0, false},
+	}
+	for _, tt := range tests {
+		rmin, rmax, ok := s.RangeAt(tt.r)
+		if ok != tt.ok || ok && (rmin != tt.rmin || rmax != tt.rmax) {
+			t.Errorf("RangeAt(%#x) = %#x, %#x, %v", tt.r, rmin, rmax, ok)
+		}
+	}
+	if r, ok := s.PrevNotIn('0'); ok {
+		t.Errorf("PrevNotIn('0') = %q, want none", r)
+	}
+	if r, ok := s.NextNotIn(0x10000); ok {
+		t.Errorf("NextNotIn(0x10000) = %q, want none", r)
+	}
+	if r, ok := s.PrevIn('z'); r != 'a'-1 || !ok {
+		t.Errorf("PrevIn('z') = %q, %v", r, ok)
+	}
+
+	a := AsciiSet{'0', '9' + 1, 'a'}
+	if c, ok := a.NextNotIn('a'); ok {
+		t.Errorf("NextNotIn('a') = %q, want none", c)
+	}
+	if c, ok := a.PrevNotIn('5'); c != '0'-1 || !ok {
+		t.Errorf("PrevNotIn('5') = %q, %v", c, ok)
+	}
+	if cmin, cmax, ok := a.RangeAt('q'); cmin != 'a' || cmax != 0x7f || !ok {
+		t.Errorf("RangeAt('q') = %q, %q, %v", cmin, cmax, ok)
+	}
+
+	// allocating the next free id
+	ids := NewDomainSet(NewDomain(1, 100))
+	ids.InsertRange(1, 41)
+	if id, ok := ids.NextNotIn(1); id != 42 || !ok {
+		t.Errorf("NextNotIn(1) = %d, %v, want 42", id, ok)
+	}
+}
@@ -0,0 +1,507 @@
+package ics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Dialect selects a character class syntax.
+type Dialect int
+
+const (
+	// Plain is the unbracketed syntax produced by RuneSet.String and
+	// AsciiSet.String: a sequence of characters and dash-separated ranges that
+	// uses Go-style escapes.
+	Plain Dialect = iota
+
+	// RE2 is the character class syntax of Go regexp and RE2, e.g.
+	// [^a-z\d\p{Greek}] or [\x00-\x{10FFFF}]. In RE2 and the other regular
+	// expression dialects, a single class escape, such as \d or \p{Greek}, is
+	// also accepted without brackets.
+	RE2
+
+	// POSIX is the POSIX bracket expression syntax, e.g. [[:alpha:]_] or
+	// []a-]. Backslashes have no special meaning.
+	POSIX
+
+	// Glob is the shell glob character class syntax, e.g. [!a-c] or [^a-c].
+	// A backslash escapes the following character.
+	Glob
+
+	// PCRE is the character class syntax of Perl compatible regular
+	// expressions, e.g. [^a-z\d\p{Greek}\x{10FFFF}].
+	PCRE
+
+	// JavaScript is the character class syntax of JavaScript regular
+	// expressions with the u flag, e.g. [^a-z\d\p{sc=Greek}\u{10FFFF}].
+	JavaScript
+
+	// Java is the character class syntax of java.util.regex, e.g.
+	// [^a-z\d\p{IsGreek}\p{Alpha}\x{10FFFF}].
+	Java
+)
+
+// is_regex indicates if d is a regular expression dialect.
+func (d Dialect) is_regex() bool {
+	return d == RE2 || d == PCRE || d == JavaScript || d == Java
+}
+
+// ParseError describes a problem with the syntax of a character class.
+type ParseError struct {
+	Offset int    // byte offset of the problem within the input
+	Msg    string // description of the problem
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("ics: %s at offset %d", e.Msg, e.Offset)
+}
+
+// ParseRuneSet parses a character class written in the syntax of the dialect
+// d.
+func ParseRuneSet(s string, d Dialect) (RuneSet, error) {
+	return parse_class(s, d, unicode.MaxRune)
+}
+
+// ParseAsciiSet parses a character class written in the syntax of the dialect
+// d. Characters outside of the ASCII range are reported as errors, while named
+// classes, such as \p{L}, and negated classes are restricted to ASCII.
+func ParseAsciiSet(s string, d Dialect) (AsciiSet, error) {
+	r, err := parse_class(s, d, 0x7f)
+	if err != nil {
+		return nil, err
+	}
+	a, _ := r.AsciiSplit()
+	return a, nil
+}
+
+type class_parser struct {
+	src string
+	pos int
+	d   Dialect
+	max rune
+	b   RuneSetBuilder
+}
+
+func parse_class(src string, d Dialect, max rune) (RuneSet, error) {
+	p := &class_parser{src: src, d: d, max: max}
+
+	var s RuneSet
+	var err error
+	switch {
+	case d == Plain:
+		for err == nil && p.pos < len(p.src) {
+			err = p.parse_item()
+		}
+		s = p.b.Build()
+	case d.is_regex() && strings.HasPrefix(src, "\\"):
+		if err = p.parse_item(); err == nil {
+			s = p.b.Build()
+		}
+	case d.is_regex() || d == POSIX || d == Glob:
+		s, err = p.parse_bracket()
+	default:
+		return nil, fmt.Errorf("ics: unsupported dialect %d", d)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.error(p.pos, "unexpected characters after the class")
+	}
+	if max < unicode.MaxRune {
+		s = Intersect(s, RuneSet{0, max + 1})
+	}
+	return s, nil
+}
+
+func (p *class_parser) error(offset int, msg string) error {
+	return &ParseError{Offset: offset, Msg: msg}
+}
+
+func (p *class_parser) parse_bracket() (RuneSet, error) {
+	start := p.pos
+	if !strings.HasPrefix(p.src[p.pos:], "[") {
+		return nil, p.error(p.pos, "missing opening [")
+	}
+	p.pos++
+
+	negate := false
+	if strings.HasPrefix(p.src[p.pos:], "^") || (p.d == Glob && strings.HasPrefix(p.src[p.pos:], "!")) {
+		negate = true
+		p.pos++
+	}
+
+	for first := true; ; first = false {
+		if p.pos >= len(p.src) {
+			return nil, p.error(start, "missing closing ]")
+		}
+		if p.src[p.pos] == ']' && (!first || p.d == JavaScript) {
+			p.pos++
+			break
+		}
+		if err := p.parse_item(); err != nil {
+			return nil, err
+		}
+	}
+
+	s := p.b.Build()
+	if negate {
+		s = s.Inverted()
+	}
+	return s, nil
+}
+
+// at_range_dash indicates if the parser is at a dash that separates range
+// endpoints, as opposed to a literal dash at the end of the class.
+func (p *class_parser) at_range_dash() bool {
+	if !strings.HasPrefix(p.src[p.pos:], "-") || p.pos+1 >= len(p.src) {
+		return false
+	}
+	return p.d == Plain || p.src[p.pos+1] != ']'
+}
+
+func (p *class_parser) parse_item() error {
+	if (p.d == RE2 || p.d == PCRE || p.d == POSIX || p.d == Glob) && strings.HasPrefix(p.src[p.pos:], "[:") {
+		s, err := p.parse_posix_class()
+		if err != nil {
+			return err
+		}
+		p.add_set(s)
+		return nil
+	}
+
+	off := p.pos
+	lo, s, err := p.parse_atom()
+	if err != nil {
+		return err
+	}
+	if s != nil {
+		if p.at_range_dash() {
+			return p.error(off, "invalid range endpoint")
+		}
+		p.add_set(s)
+		return nil
+	}
+	if !p.at_range_dash() {
+		p.b.Add(lo)
+		return nil
+	}
+
+	p.pos++
+	hi_off := p.pos
+	hi, s, err := p.parse_atom()
+	if err != nil {
+		return err
+	}
+	if s != nil {
+		return p.error(hi_off, "invalid range endpoint")
+	}
+	if hi < lo {
+		return p.error(off, "invalid range")
+	}
+	p.b.AddRange(lo, hi)
+	return nil
+}
+
+func (p *class_parser) add_set(s RuneSet) {
+	for lo, hi := range s.Ranges() {
+		p.b.AddRange(lo, hi)
+	}
+}
+
+// parse_atom parses either a single character or an escaped class.
+func (p *class_parser) parse_atom() (rune, RuneSet, error) {
+	off := p.pos
+	if p.d == POSIX && (strings.HasPrefix(p.src[p.pos:], "[=") || strings.HasPrefix(p.src[p.pos:], "[.")) {
+		// equivalence classes and collating symbols are limited to single
+		// characters
+		term := p.src[p.pos+1:p.pos+2] + "]"
+		i := strings.Index(p.src[p.pos+2:], term)
+		if i < 0 {
+			return 0, nil, p.error(off, "missing closing "+term)
+		}
+		body := p.src[p.pos+2 : p.pos+2+i]
+		c, w := utf8.DecodeRuneInString(body)
+		if w == 0 || w != len(body) {
+			return 0, nil, p.error(off, "unsupported multi-character collating element")
+		}
+		p.pos += 2 + i + 2
+		return p.check(off, c)
+	}
+
+	c, w := utf8.DecodeRuneInString(p.src[p.pos:])
+	if c == utf8.RuneError && w == 1 {
+		return 0, nil, p.error(off, "invalid UTF-8")
+	}
+	if c == '\\' && p.d != POSIX {
+		return p.parse_escape()
+	}
+	p.pos += w
+	return p.check(off, c)
+}
+
+func (p *class_parser) check(off int, c rune) (rune, RuneSet, error) {
+	if c > p.max {
+		return 0, nil, p.error(off, "character out of range")
+	}
+	return c, nil, nil
+}
+
+func (p *class_parser) parse_escape() (rune, RuneSet, error) {
+	off := p.pos
+	p.pos++
+	if p.pos >= len(p.src) {
+		return 0, nil, p.error(off, "trailing backslash")
+	}
+
+	if p.d == Glob {
+		c, w := utf8.DecodeRuneInString(p.src[p.pos:])
+		if c == utf8.RuneError && w == 1 {
+			return 0, nil, p.error(p.pos, "invalid UTF-8")
+		}
+		p.pos += w
+		return p.check(off, c)
+	}
+
+	c := p.src[p.pos]
+	p.pos++
+	switch c {
+	case 'a':
+		return '\a', nil, nil
+	case 'b':
+		return '\b', nil, nil
+	case 'f':
+		return '\f', nil, nil
+	case 'n':
+		return '\n', nil, nil
+	case 'r':
+		return '\r', nil, nil
+	case 't':
+		return '\t', nil, nil
+	case 'v':
+		return '\v', nil, nil
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		v := rune(c - '0')
+		for i := 0; i < 2 && p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '7'; i++ {
+			v = v*8 + rune(p.src[p.pos]-'0')
+			p.pos++
+		}
+		return p.check(off, v)
+	case 'x':
+		if strings.HasPrefix(p.src[p.pos:], "{") {
+			return p.parse_braced_hex(off)
+		}
+		return p.parse_hex(off, 2)
+	case 'u':
+		if strings.HasPrefix(p.src[p.pos:], "{") {
+			return p.parse_braced_hex(off)
+		}
+		return p.parse_hex(off, 4)
+	case 'U':
+		return p.parse_hex(off, 8)
+	case 'd', 'D', 's', 'S', 'w', 'W':
+		s := perl_classes[unicode.ToLower(rune(c))]
+		if c == 's' || c == 'S' {
+			switch p.d {
+			case PCRE, Java:
+				s = posix_classes["space"]
+			case JavaScript:
+				s = js_space()
+			}
+		}
+		if unicode.IsUpper(rune(c)) {
+			s = s.Inverted()
+		}
+		return 0, s, nil
+	case 'p', 'P':
+		return p.parse_unicode_class(off, c == 'P')
+	}
+	if c < utf8.RuneSelf && !is_word_char(c) {
+		return rune(c), nil, nil
+	}
+	return 0, nil, p.error(off, "invalid escape sequence")
+}
+
+func is_word_char(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c == '_'
+}
+
+func (p *class_parser) parse_hex(off int, n int) (rune, RuneSet, error) {
+	if p.pos+n > len(p.src) {
+		return 0, nil, p.error(off, "invalid escape sequence")
+	}
+	v, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+	if err != nil {
+		return 0, nil, p.error(off, "invalid escape sequence")
+	}
+	p.pos += n
+	if v > unicode.MaxRune {
+		return 0, nil, p.error(off, "invalid codepoint")
+	}
+	return p.check(off, rune(v))
+}
+
+func (p *class_parser) parse_braced_hex(off int) (rune, RuneSet, error) {
+	i := strings.IndexByte(p.src[p.pos:], '}')
+	if i < 2 {
+		return 0, nil, p.error(off, "invalid escape sequence")
+	}
+	v, err := strconv.ParseUint(p.src[p.pos+1:p.pos+i], 16, 32)
+	if err != nil {
+		return 0, nil, p.error(off, "invalid escape sequence")
+	}
+	p.pos += i + 1
+	if v > unicode.MaxRune {
+		return 0, nil, p.error(off, "invalid codepoint")
+	}
+	return p.check(off, rune(v))
+}
+
+func (p *class_parser) parse_unicode_class(off int, negate bool) (rune, RuneSet, error) {
+	if p.pos >= len(p.src) {
+		return 0, nil, p.error(off, "invalid unicode class")
+	}
+	var name string
+	if p.src[p.pos] == '{' {
+		i := strings.IndexByte(p.src[p.pos:], '}')
+		if i < 0 {
+			return 0, nil, p.error(off, "missing closing }")
+		}
+		name = p.src[p.pos+1 : p.pos+i]
+		p.pos += i + 1
+		if strings.HasPrefix(name, "^") {
+			name = name[1:]
+			negate = !negate
+		}
+	} else {
+		c, w := utf8.DecodeRuneInString(p.src[p.pos:])
+		name = string(c)
+		p.pos += w
+	}
+
+	s, ok := p.lookup_class(name)
+	if !ok {
+		return 0, nil, p.error(off, "unknown unicode class "+strconv.Quote(name))
+	}
+	if negate {
+		s = s.Inverted()
+	}
+	return 0, s, nil
+}
+
+// lookup_class looks up a named class in the syntax of the dialect.
+func (p *class_parser) lookup_class(name string) (RuneSet, bool) {
+	switch p.d {
+	case PCRE:
+		if name == "L&" {
+			name = "LC"
+		}
+	case JavaScript, Java:
+		if p.d == Java {
+			if posix, ok := java_posix_classes[name]; ok {
+				return posix_classes[posix], true
+			}
+			name = strings.TrimPrefix(name, "Is")
+		}
+		if k, v, ok := strings.Cut(name, "="); ok {
+			var t *unicode.RangeTable
+			switch k {
+			case "sc", "Script":
+				t = unicode.Scripts[v]
+			case "gc", "General_Category":
+				t = unicode.Categories[v]
+			}
+			if t == nil {
+				return nil, false
+			}
+			return FromRangeTable(t), true
+		}
+	}
+	return unicode_class(name)
+}
+
+// js_space returns the set matched by \s in JavaScript.
+func js_space() RuneSet {
+	return Union(FromRangeTable(unicode.Zs), RuneSet{'\t', '\r' + 1, 0x2028, 0x2029 + 1, 0xfeff, 0xfeff + 1})
+}
+
+// unicode_class looks up a unicode category, script, or property by name.
+func unicode_class(name string) (RuneSet, bool) {
+	if name == "Any" {
+		return RuneSet{0}, true
+	}
+	for _, tables := range []map[string]*unicode.RangeTable{unicode.Categories, unicode.Scripts, unicode.Properties} {
+		if t, ok := tables[name]; ok {
+			return FromRangeTable(t), true
+		}
+	}
+	return nil, false
+}
+
+func (p *class_parser) parse_posix_class() (RuneSet, error) {
+	off := p.pos
+	i := strings.Index(p.src[p.pos+2:], ":]")
+	if i < 0 {
+		return nil, p.error(off, "missing closing :]")
+	}
+	name := p.src[p.pos+2 : p.pos+2+i]
+	p.pos += 2 + i + 2
+
+	negate := false
+	if strings.HasPrefix(name, "^") && p.d == RE2 {
+		name = name[1:]
+		negate = true
+	}
+	s, ok := posix_classes[name]
+	if !ok {
+		return nil, p.error(off, "unknown POSIX class "+strconv.Quote(name))
+	}
+	if negate {
+		s = s.Inverted()
+	}
+	return s, nil
+}
+
+var perl_classes = map[rune]RuneSet{
+	'd': {'0', '9' + 1},
+	's': {'\t', '\n' + 1, '\f', '\r' + 1, ' ', ' ' + 1},
+	'w': {'0', '9' + 1, 'A', 'Z' + 1, '_', '_' + 1, 'a', 'z' + 1},
+}
+
+var posix_classes = map[string]RuneSet{
+	"alnum":  {'0', '9' + 1, 'A', 'Z' + 1, 'a', 'z' + 1},
+	"alpha":  {'A', 'Z' + 1, 'a', 'z' + 1},
+	"ascii":  {0x00, 0x7f + 1},
+	"blank":  {'\t', '\t' + 1, ' ', ' ' + 1},
+	"cntrl":  {0x00, 0x1f + 1, 0x7f, 0x7f + 1},
+	"digit":  {'0', '9' + 1},
+	"graph":  {'!', '~' + 1},
+	"lower":  {'a', 'z' + 1},
+	"print":  {' ', '~' + 1},
+	"punct":  {'!', '/' + 1, ':', '@' + 1, '[', '`' + 1, '{', '~' + 1},
+	"space":  {'\t', '\r' + 1, ' ', ' ' + 1},
+	"upper":  {'A', 'Z' + 1},
+	"word":   {'0', '9' + 1, 'A', 'Z' + 1, '_', '_' + 1, 'a', 'z' + 1},
+	"xdigit": {'0', '9' + 1, 'A', 'F' + 1, 'a', 'f' + 1},
+}
+
+// java_posix_classes maps the names of Java POSIX classes, e.g. \p{Alpha}, to
+// the names of the corresponding POSIX classes.
+var java_posix_classes = map[string]string{
+	"ASCII":  "ascii",
+	"Alnum":  "alnum",
+	"Alpha":  "alpha",
+	"Blank":  "blank",
+	"Cntrl":  "cntrl",
+	"Digit":  "digit",
+	"Graph":  "graph",
+	"Lower":  "lower",
+	"Print":  "print",
+	"Punct":  "punct",
+	"Space":  "space",
+	"Upper":  "upper",
+	"XDigit": "xdigit",
+}
@@ -0,0 +1,155 @@
+package ics
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestParseRuneSet(t *testing.T) {
+	tests := []struct {
+		src  string
+		d    Dialect
+		want string
+	}{
+		{"", Plain, ""},
+		{"a-z0-9_", Plain, "0-9_a-z"},
+		{`\x00-\x1F\-\\`, Plain, `\x00-\x1F\-\\`},
+		{`α-ω\U0010FFFF`, Plain, `\u03B1-\u03C9\U0010FFFF`},
+		{"-a-", Plain, `\-a`},
+		{"é", Plain, `\u00E9`},
+
+		{"[a-z]", RE2, "a-z"},
+		{"[^a-z]", RE2, `\x00-` + "`" + `{-\U0010FFFF`},
+		{`[\d_]`, RE2, "0-9_"},
+		{`[\x41-\x{5A}]`, RE2, "A-Z"},
+		{`[\x{10FFFF}]`, RE2, `\U0010FFFF`},
+		{`[\101]`, RE2, "A"},
+		{`[]a]`, RE2, "]a"},
+		{`[a-]`, RE2, `\-a`},
+		{`[-a]`, RE2, `\-a`},
+		{`[\]\[\^\-]`, RE2, `\-[]^`},
+		{`[[:alpha:]]`, RE2, "A-Za-z"},
+		{`[[:^alpha:]]`, RE2, `\x00-@[-` + "`" + `{-\U0010FFFF`},
+		{`\d`, RE2, "0-9"},
+		{`\pN`, RE2, FromRangeTable(unicode.N).String()},
+		{`\p{Greek}`, RE2, FromRangeTable(unicode.Greek).String()},
+		{`[^\P{Greek}]`, RE2, FromRangeTable(unicode.Greek).String()},
+		{`[\p{^Greek}]`, RE2, FromRangeTable(unicode.Greek).Inverted().String()},
+
+		{"[[:alpha:]_]", POSIX, "A-Z_a-z"},
+		{"[]a-]", POSIX, `\-]a`},
+		{`[\a]`, POSIX, `\\a`},
+		{"[^[:digit:]]", POSIX, `\x00-/:-\U0010FFFF`},
+		{"[[=a=][.b.]-c]", POSIX, "a-c"},
+
+		{"[!a-c]", Glob, `\x00-` + "`" + `d-\U0010FFFF`},
+		{"[^a-c]", Glob, `\x00-` + "`" + `d-\U0010FFFF`},
+		{`[\!\]]`, Glob, "!]"},
+		{"[[:upper:]]", Glob, "A-Z"},
+
+		{`[\d\p{sc=Greek}]`, JavaScript, "0-9" + FromRangeTable(unicode.Greek).String()},
+		{`[\u{41}-\u005A]`, JavaScript, "A-Z"},
+		{`[]`, JavaScript, ""},
+		{`[^]`, JavaScript, `\x00-\U0010FFFF`},
+		{`[\p{IsGreek}\d]`, Java, "0-9" + FromRangeTable(unicode.Greek).String()},
+		{`\p{Alpha}`, Java, "A-Za-z"},
+		{`[\&\x{41}]`, Java, "&A"},
+		{`[\s]`, PCRE, `\t-\r `},
+		{`\p{L&}`, PCRE, FromRangeTable(unicode.Categories["LC"]).String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := ParseRuneSet(tt.src, tt.d)
+			if err != nil {
+				t.Fatalf("ParseRuneSet(%q) failed: %v", tt.src, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseRuneSet(%q) = %v, want %v", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRuneSet_Errors(t *testing.T) {
+	tests := []struct {
+		src    string
+		d      Dialect
+		offset int
+	}{
+		{"a-", RE2, 0},
+		{"[a-z", RE2, 0},
+		{"[z-a]", RE2, 1},
+		{`[a\q]`, RE2, 2},
+		{`[\p{Klingon}]`, RE2, 1},
+		{`[\x{110000}]`, RE2, 1},
+		{`[\d-z]`, RE2, 1},
+		{`[a-\d]`, RE2, 3},
+		{`[[:alpah:]]`, POSIX, 1},
+		{"[a]b", Glob, 3},
+		{"a\\", Plain, 1},
+		{"a\xff", Plain, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := ParseRuneSet(tt.src, tt.d)
+			var pe *ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("ParseRuneSet(%q) error = %v, want ParseError", tt.src, err)
+			}
+			if pe.Offset != tt.offset {
+				t.Errorf("ParseRuneSet(%q) error = %v, want offset %d", tt.src, err, tt.offset)
+			}
+		})
+	}
+}
+
+func TestParseAsciiSet(t *testing.T) {
+	got, err := ParseAsciiSet(`[^\pL]`, RE2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (AsciiSet{0, 'A', 'Z' + 1, 'a', 'z' + 1}); !slices.Equal(got, want) {
+		t.Errorf("ParseAsciiSet = %v, want %v", got, want)
+	}
+
+	if _, err := ParseAsciiSet("[a-é]", RE2); err == nil {
+		t.Errorf("ParseAsciiSet accepted a non-ascii character")
+	}
+}
+
+func TestParseRuneSet_RoundTrip(t *testing.T) {
+	for iter := 0; iter < 1000; iter++ {
+		var s RuneSet
+		for i, n := 0, rand.Intn(5); i < n; i++ {
+			lo := rune(rand.Intn(0x80))
+			if rand.Intn(4) == 0 {
+				lo = rune(rand.Intn(unicode.MaxRune + 1))
+			}
+			hi := lo + rune(rand.Intn(3))
+			if hi > unicode.MaxRune {
+				hi = unicode.MaxRune
+			}
+			s.InsertRange(lo, hi)
+		}
+		got, err := ParseRuneSet(s.String(), Plain)
+		if err != nil {
+			t.Fatalf("ParseRuneSet(%q) failed: %v", s.String(), err)
+		}
+		if !slices.Equal(got, s) {
+			t.Fatalf("ParseRuneSet(%q) = %v, want %v", s.String(), got, s)
+		}
+
+		a, _ := s.AsciiSplit()
+		got_a, err := ParseAsciiSet(a.String(), Plain)
+		if err != nil {
+			t.Fatalf("ParseAsciiSet(%q) failed: %v", a.String(), err)
+		}
+		if !slices.Equal(got_a, a) {
+			t.Fatalf("ParseAsciiSet(%q) = %v, want %v", a.String(), got_a, a)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package ics
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
+)
+
+// exists walks the boundaries of a and b the same way as combine does and
+// reports if the combined set would contain any elements.
+func exists[S ~[]T, T constraints.Ordered](a, b S, op set_op) bool {
+	i, j, na, nb := 0, 0, len(a), len(b)
+	for i < na || j < nb {
+		if j == nb || (i < na && a[i] < b[j]) {
+			i++
+		} else if i == na || b[j] < a[i] {
+			j++
+		} else {
+			i++
+			j++
+		}
+		if op.eval(i&1 == 1, j&1 == 1) {
+			return true
+		}
+	}
+	return false
+}
+
+// Equal indicates if a and b contain the same elements.
+func Equal[S ~[]T, T constraints.Ordered](a, b S) bool {
+	return slices.Equal(a, b)
+}
+
+// IsSubset indicates if all elements of a are also contained in b.
+func IsSubset[S ~[]T, T constraints.Ordered](a, b S) bool {
+	return !exists(a, b, op_difference)
+}
+
+// IsSuperset indicates if all elements of b are also contained in a.
+func IsSuperset[S ~[]T, T constraints.Ordered](a, b S) bool {
+	return !exists(b, a, op_difference)
+}
+
+// Overlaps indicates if a and b have any elements in common.
+func Overlaps[S ~[]T, T constraints.Ordered](a, b S) bool {
+	return exists(a, b, op_intersection)
+}
+
+// Disjoint indicates if a and b have no elements in common.
+func Disjoint[S ~[]T, T constraints.Ordered](a, b S) bool {
+	return !exists(a, b, op_intersection)
+}
+
+// ContainsInterval indicates if an interval is fully covered by s.
+//
+//   - if l < h, a bounded interval [l,h) is tested
+//   - if l >= h, a half-open interval [l,... is tested instead
+func ContainsInterval[S ~[]T, T constraints.Ordered](s S, l, h T) bool {
+	// i is the number of boundaries that are less than or equal to l
+	i, be := search(s, l)
+	if be {
+		i++
+	}
+	if i&1 == 0 {
+		return false
+	} else if i == len(s) {
+		return true
+	} else {
+		return l < h && h <= s[i]
+	}
+}
+
+// IsSubset indicates if all elements of s are also contained in o.
+func (s Set[T]) IsSubset(o Set[T]) bool {
+	return IsSubset(s, o)
+}
+
+// IsSuperset indicates if all elements of o are also contained in s.
+func (s Set[T]) IsSuperset(o Set[T]) bool {
+	return IsSuperset(s, o)
+}
+
+// Overlaps indicates if s and o have any elements in common.
+func (s Set[T]) Overlaps(o Set[T]) bool {
+	return Overlaps(s, o)
+}
+
+// Disjoint indicates if s and o have no elements in common.
+func (s Set[T]) Disjoint(o Set[T]) bool {
+	return Disjoint(s, o)
+}
+
+// ContainsInterval indicates if an interval is fully covered by s. See
+// ContainsInterval for details.
+func (s Set[T]) ContainsInterval(l, h T) bool {
+	return ContainsInterval(s, l, h)
+}
+
+// Equal indicates if s and o contain the same codepoints.
+func (s RuneSet) Equal(o RuneSet) bool {
+	return Equal(s, o)
+}
+
+// IsSubset indicates if all codepoints of s are also contained in o.
+func (s RuneSet) IsSubset(o RuneSet) bool {
+	return IsSubset(s, o)
+}
+
+// IsSuperset indicates if all codepoints of o are also contained in s.
+func (s RuneSet) IsSuperset(o RuneSet) bool {
+	return IsSuperset(s, o)
+}
+
+// Overlaps indicates if s and o have any codepoints in common.
+func (s RuneSet) Overlaps(o RuneSet) bool {
+	return Overlaps(s, o)
+}
+
+// Disjoint indicates if s and o have no codepoints in common.
+func (s RuneSet) Disjoint(o RuneSet) bool {
+	return Disjoint(s, o)
+}
+
+// ContainsRange indicates if an inclusive [rmin,rmax] range of unicode
+// codepoints is fully covered by s.
+func (s RuneSet) ContainsRange(rmin, rmax rune) bool {
+	if rmax < rmin || rmin < 0 || rmax > utf8.MaxRune {
+		return false
+	}
+	if rmax == utf8.MaxRune {
+		return ContainsInterval(s, rmin, rmin)
+	}
+	return ContainsInterval(s, rmin, rmax+1)
+}
+
+// Equal indicates if s and o contain the same characters.
+func (s AsciiSet) Equal(o AsciiSet) bool {
+	return Equal(s, o)
+}
+
+// IsSubset indicates if all characters of s are also contained in o.
+func (s AsciiSet) IsSubset(o AsciiSet) bool {
+	return IsSubset(s, o)
+}
+
+// IsSuperset indicates if all characters of o are also contained in s.
+func (s AsciiSet) IsSuperset(o AsciiSet) bool {
+	return IsSuperset(s, o)
+}
+
+// Overlaps indicates if s and o have any characters in common.
+func (s AsciiSet) Overlaps(o AsciiSet) bool {
+	return Overlaps(s, o)
+}
+
+// Disjoint indicates if s and o have no characters in common.
+func (s AsciiSet) Disjoint(o AsciiSet) bool {
+	return Disjoint(s, o)
+}
+
+// ContainsRange indicates if an inclusive [cmin,cmax] range of ascii
+// characters is fully covered by s.
+func (s AsciiSet) ContainsRange(cmin, cmax byte) bool {
+	if cmax < cmin || cmax > 0x7f {
+		return false
+	}
+	if cmax == 0x7f {
+		return ContainsInterval(s, cmin, cmin)
+	}
+	return ContainsInterval(s, cmin, cmax+1)
+}
@@ -0,0 +1,75 @@
+package ics
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPredicates(t *testing.T) {
+	for iter := 0; iter < 1000; iter++ {
+		a := random_byteset(rand.Intn(5))
+		b := random_byteset(rand.Intn(5))
+
+		subset, superset, overlaps := true, true, false
+		for v := 0; v < 256; v++ {
+			ina, inb := Contains(a, byte(v)), Contains(b, byte(v))
+			if ina && !inb {
+				subset = false
+			}
+			if inb && !ina {
+				superset = false
+			}
+			if ina && inb {
+				overlaps = true
+			}
+		}
+
+		if got := IsSubset(a, b); got != subset {
+			t.Fatalf("IsSubset(%v, %v) = %v, want %v", a, b, got, subset)
+		}
+		if got := IsSuperset(a, b); got != superset {
+			t.Fatalf("IsSuperset(%v, %v) = %v, want %v", a, b, got, superset)
+		}
+		if got := Overlaps(a, b); got != overlaps {
+			t.Fatalf("Overlaps(%v, %v) = %v, want %v", a, b, got, overlaps)
+		}
+		if got := Disjoint(a, b); got == overlaps {
+			t.Fatalf("Disjoint(%v, %v) = %v, want %v", a, b, got, !overlaps)
+		}
+		if got := Equal(a, b); got != (subset && superset) {
+			t.Fatalf("Equal(%v, %v) = %v, want %v", a, b, got, subset && superset)
+		}
+
+		l, h := byte(rand.Intn(256)), byte(rand.Intn(256))
+		covered := true
+		for v := int(l); v < 256 && (h <= l || v < int(h)); v++ {
+			covered = covered && Contains(a, byte(v))
+		}
+		if got := ContainsInterval(a, l, h); got != covered {
+			t.Fatalf("ContainsInterval(%v, %d, %d) = %v, want %v", a, l, h, got, covered)
+		}
+	}
+}
+
+func TestRuneSet_ContainsRange(t *testing.T) {
+	s := RuneSet{'a', 'z' + 1, 0x10000}
+	tests := []struct {
+		rmin, rmax rune
+		want       bool
+	}{
+		{'a', 'z', true},
+		{'b', 'y', true},
+		{'a', '{', false},
+		{'`', 'z', false},
+		{0x10000, 0x10ffff, true},
+		{0x20000, 0x10ffff, true},
+		{0xffff, 0x10ffff, false},
+		{'z', 'a', false},
+		{0x20000, 0x110000, false},
+	}
+	for _, tt := range tests {
+		if got := s.ContainsRange(tt.rmin, tt.rmax); got != tt.want {
+			t.Errorf("RuneSet[%v].ContainsRange(%#x, %#x) = %v, want %v", s, tt.rmin, tt.rmax, got, tt.want)
+		}
+	}
+}
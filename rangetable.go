@@ -0,0 +1,122 @@
+package ics
+
+import (
+	"unicode"
+)
+
+// FromRangeTable returns a RuneSet that contains all codepoints from t.
+func FromRangeTable(t *unicode.RangeTable) RuneSet {
+	return FromRangeTables(t)
+}
+
+// FromRangeTables returns a RuneSet that contains all codepoints from any of
+// the tables.
+func FromRangeTables(ts ...*unicode.RangeTable) RuneSet {
+	var b RuneSetBuilder
+	for _, t := range ts {
+		if t == nil {
+			continue
+		}
+		for _, r := range t.R16 {
+			add_strided(&b, rune(r.Lo), rune(r.Hi), rune(r.Stride))
+		}
+		for _, r := range t.R32 {
+			add_strided(&b, rune(r.Lo), rune(r.Hi), rune(r.Stride))
+		}
+	}
+	return b.Build()
+}
+
+func add_strided(b *RuneSetBuilder, lo, hi, stride rune) {
+	if stride == 1 {
+		b.AddRange(lo, hi)
+		return
+	}
+	for r := lo; r <= hi; r += stride {
+		b.Add(r)
+	}
+}
+
+// RangeTable returns a unicode.RangeTable that contains the same codepoints as
+// s, so that the set can be used with unicode.Is, unicode.In, and friends.
+//
+// Isolated codepoints and pairs of adjacent codepoints are packed into the
+// least number of evenly spaced (strided) ranges.
+func (s RuneSet) RangeTable() *unicode.RangeTable {
+	t := &unicode.RangeTable{}
+
+	var points []rune
+	flush := func() {
+		append_progressions(t, points)
+		points = points[:0]
+	}
+
+	for lo, hi := range s.Ranges() {
+		if hi-lo >= 2 {
+			flush()
+			if lo <= 0xffff && hi > 0xffff {
+				append_table_range(t, lo, 0xffff, 1)
+				append_table_range(t, 0x10000, hi, 1)
+			} else {
+				append_table_range(t, lo, hi, 1)
+			}
+			continue
+		}
+		for r := lo; r <= hi; r++ {
+			// progressions may not cross the boundary between R16 and R32
+			if n := len(points); n > 0 && (points[n-1] > 0xffff) != (r > 0xffff) {
+				flush()
+			}
+			points = append(points, r)
+		}
+	}
+	flush()
+	return t
+}
+
+// append_progressions partitions sorted points into the least number of
+// arithmetic progressions and appends them to t as strided ranges.
+func append_progressions(t *unicode.RangeTable, p []rune) {
+	n := len(p)
+	if n == 0 {
+		return
+	}
+
+	// cost[i] is the least number of progressions that cover p[:i], the last
+	// of these progressions starts at from[i]; the cost never decreases with
+	// i, so the best choice is always the longest progression ending at p[i-1]
+	cost := make([]int, n+1)
+	from := make([]int, n+1)
+	st := 0
+	for i := 1; i <= n; i++ {
+		k := i - 1
+		if k >= 2 && p[k]-p[k-1] != p[k-1]-p[k-2] {
+			st = k - 1
+		}
+		cost[i] = cost[st] + 1
+		from[i] = st
+	}
+
+	segs := make([][2]int, cost[n])
+	for i, j := n, cost[n]-1; i > 0; i, j = from[i], j-1 {
+		segs[j] = [2]int{from[i], i}
+	}
+	for _, seg := range segs {
+		lo, hi, stride := p[seg[0]], p[seg[1]-1], rune(1)
+		if seg[1]-seg[0] > 1 {
+			stride = p[seg[0]+1] - p[seg[0]]
+		}
+		append_table_range(t, lo, hi, stride)
+	}
+}
+
+func append_table_range(t *unicode.RangeTable, lo, hi, stride rune) {
+	if hi <= 0xffff {
+		t.R16 = append(t.R16, unicode.Range16{Lo: uint16(lo), Hi: uint16(hi), Stride: uint16(stride)})
+		if hi <= unicode.MaxLatin1 {
+			t.LatinOffset++
+		}
+	} else {
+		t.R32 = append(t.R32, unicode.Range32{Lo: uint32(lo), Hi: uint32(hi), Stride: uint32(stride)})
+	}
+}
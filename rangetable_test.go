@@ -0,0 +1,71 @@
+package ics
+
+import (
+	"testing"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestFromRangeTable(t *testing.T) {
+	tables := map[string]*unicode.RangeTable{
+		"Lu":          unicode.Lu,
+		"Ll":          unicode.Ll,
+		"Nd":          unicode.Nd,
+		"Greek":       unicode.Greek,
+		"White_Space": unicode.White_Space,
+	}
+	for name, table := range tables {
+		t.Run(name, func(t *testing.T) {
+			s := FromRangeTable(table)
+			rt := s.RangeTable()
+			for r := rune(0); r <= unicode.MaxRune; r++ {
+				want := unicode.Is(table, r)
+				if got := s.Contains(r); got != want {
+					t.Fatalf("FromRangeTable(%s).Contains(%#x) = %v, want %v", name, r, got, want)
+				}
+				if got := unicode.Is(rt, r); got != want {
+					t.Fatalf("RangeTable() of %s: unicode.Is(%#x) = %v, want %v", name, r, got, want)
+				}
+			}
+			if rt.LatinOffset != table.LatinOffset {
+				t.Errorf("RangeTable() of %s: LatinOffset = %d, want %d", name, rt.LatinOffset, table.LatinOffset)
+			}
+			if n, want := len(rt.R16)+len(rt.R32), len(table.R16)+len(table.R32); n > want {
+				t.Errorf("RangeTable() of %s has %d ranges, the original table has %d", name, n, want)
+			}
+			if got := FromRangeTable(rt); !slices.Equal(got, s) {
+				t.Errorf("RangeTable() of %s does not round-trip", name)
+			}
+		})
+	}
+}
+
+func TestRuneSet_RangeTable(t *testing.T) {
+	s := RuneSet{'a', 'a' + 1, 'c', 'c' + 1, 'e', 'e' + 1, 'x', 'z' + 1, 0xfffe, 0x10002}
+	want := &unicode.RangeTable{
+		R16: []unicode.Range16{
+			{Lo: 'a', Hi: 'e', Stride: 2},
+			{Lo: 'x', Hi: 'z', Stride: 1},
+			{Lo: 0xfffe, Hi: 0xffff, Stride: 1},
+		},
+		R32: []unicode.Range32{
+			{Lo: 0x10000, Hi: 0x10001, Stride: 1},
+		},
+		LatinOffset: 2,
+	}
+	got := s.RangeTable()
+	if !slices.Equal(got.R16, want.R16) || !slices.Equal(got.R32, want.R32) || got.LatinOffset != want.LatinOffset {
+		t.Errorf("RuneSet[%v].RangeTable() = %+v, want %+v", s, got, want)
+	}
+	if !unicode.In('c', got) || unicode.In('d', got) {
+		t.Errorf("unexpected containment in RangeTable()")
+	}
+}
+
+func TestFromRangeTables(t *testing.T) {
+	s := FromRangeTables(unicode.Nd, nil, unicode.Lu)
+	if !s.Contains('5') || !s.Contains('Q') || s.Contains('q') {
+		t.Errorf("unexpected containment in FromRangeTables(Nd, Lu)")
+	}
+}
@@ -0,0 +1,190 @@
+package ics
+
+import (
+	"sort"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Rank returns the number of elements of s that are less than x. Open-ended
+// intervals extend up to the largest value of T.
+//
+// Rank walks the intervals of s, so it takes O(n) time. Use RankIndex for
+// repeated queries.
+func Rank[S ~[]T, T constraints.Integer](s S, x T) uint64 {
+	var r uint64
+	for i := 0; i < len(s); i += 2 {
+		l := s[i]
+		if x <= l {
+			break
+		}
+		if i+1 == len(s) || x <= s[i+1] {
+			r += uint64(x) - uint64(l)
+			break
+		}
+		r += uint64(s[i+1]) - uint64(l)
+	}
+	return r
+}
+
+// Select returns the k-th smallest element of s, counting from zero. Returns
+// false if s has no more than k elements. Open-ended intervals extend up to
+// the largest value of T.
+//
+// Select walks the intervals of s, so it takes O(n) time. Use RankIndex for
+// repeated queries.
+func Select[S ~[]T, T constraints.Integer](s S, k uint64) (T, bool) {
+	return domain_select(FullDomain[T](), s, k)
+}
+
+// domain_rank is Rank with x clipped to the domain.
+func domain_rank[S ~[]T, T constraints.Integer](d Domain[T], s S, x T) uint64 {
+	switch {
+	case x < d.Min:
+		return 0
+	case x > d.Max:
+		return domain_count(d, s)
+	}
+	return Rank(s, x)
+}
+
+// domain_select is Select with open-ended intervals extending up to d.Max.
+func domain_select[S ~[]T, T constraints.Integer](d Domain[T], s S, k uint64) (T, bool) {
+	for i := 0; i < len(s); i += 2 {
+		l := s[i]
+		if i+1 == len(s) {
+			if k <= uint64(d.Max)-uint64(l) {
+				return l + T(k), true
+			}
+			break
+		}
+		n := uint64(s[i+1]) - uint64(l)
+		if k < n {
+			return l + T(k), true
+		}
+		k -= n
+	}
+	return d.Min, false
+}
+
+// RankIndex answers Rank and Select queries in O(log n) time with prefix sums
+// of the interval lengths of a set. The set must not be modified while the
+// index is in use.
+type RankIndex[T constraints.Integer] struct {
+	domain Domain[T]
+	set    []T
+	prefix []uint64 // number of elements before each interval
+	closed uint64   // number of elements in bounded intervals
+	count  uint64
+}
+
+// NewRankIndex builds an index for s. Open-ended intervals extend up to the
+// largest value of T.
+func NewRankIndex[S ~[]T, T constraints.Integer](s S) *RankIndex[T] {
+	return new_rank_index(FullDomain[T](), s)
+}
+
+func new_rank_index[S ~[]T, T constraints.Integer](d Domain[T], s S) *RankIndex[T] {
+	x := &RankIndex[T]{domain: d, set: s, prefix: make([]uint64, 0, (len(s)+1)/2)}
+	for i := 0; i < len(s); i += 2 {
+		x.prefix = append(x.prefix, x.closed)
+		if i+1 < len(s) {
+			x.closed += uint64(s[i+1]) - uint64(s[i])
+		}
+	}
+	x.count = domain_count(d, s)
+	return x
+}
+
+// Count returns the number of elements in the indexed set. The result
+// saturates at math.MaxUint64 for a full 64-bit domain.
+func (x *RankIndex[T]) Count() uint64 {
+	return x.count
+}
+
+// Rank returns the number of elements that are less than v.
+func (x *RankIndex[T]) Rank(v T) uint64 {
+	switch {
+	case v < x.domain.Min:
+		return 0
+	case v > x.domain.Max:
+		return x.count
+	}
+	k := count_le(x.set, v)
+	if k&1 == 1 {
+		return x.prefix[k/2] + uint64(v) - uint64(x.set[k-1])
+	}
+	if k/2 < len(x.prefix) {
+		return x.prefix[k/2]
+	}
+	return x.closed
+}
+
+// Select returns the k-th smallest element, counting from zero. Returns false
+// if there are no more than k elements.
+func (x *RankIndex[T]) Select(k uint64) (T, bool) {
+	j := sort.Search(len(x.prefix), func(j int) bool {
+		return x.prefix[j] > k
+	}) - 1
+	if j < 0 {
+		return x.domain.Min, false
+	}
+	k -= x.prefix[j]
+	l := x.set[2*j]
+	if 2*j+1 < len(x.set) {
+		if k < uint64(x.set[2*j+1])-uint64(l) {
+			return l + T(k), true
+		}
+	} else if k <= uint64(x.domain.Max)-uint64(l) {
+		return l + T(k), true
+	}
+	return x.domain.Min, false
+}
+
+// Rank returns the number of runes of s that are less than r.
+func (s RuneSet) Rank(r rune) uint64 {
+	return domain_rank(RuneDomain, s, r)
+}
+
+// Select returns the k-th smallest rune of s, counting from zero. Returns
+// false if s has no more than k runes.
+func (s RuneSet) Select(k uint64) (rune, bool) {
+	return domain_select(RuneDomain, s, k)
+}
+
+// RankIndex builds an index for repeated Rank and Select queries.
+func (s RuneSet) RankIndex() *RankIndex[rune] {
+	return new_rank_index(RuneDomain, s)
+}
+
+// Rank returns the number of characters of s that are less than c.
+func (s AsciiSet) Rank(c byte) uint64 {
+	return domain_rank(AsciiDomain, s, c)
+}
+
+// Select returns the k-th smallest character of s, counting from zero.
+// Returns false if s has no more than k characters.
+func (s AsciiSet) Select(k uint64) (byte, bool) {
+	return domain_select(AsciiDomain, s, k)
+}
+
+// RankIndex builds an index for repeated Rank and Select queries.
+func (s AsciiSet) RankIndex() *RankIndex[byte] {
+	return new_rank_index(AsciiDomain, s)
+}
+
+// Rank returns the number of values of s that are less than v.
+func (s DomainSet[T]) Rank(v T) uint64 {
+	return domain_rank(s.Domain, s.Set, v)
+}
+
+// Select returns the k-th smallest value of s, counting from zero. Returns
+// false if s has no more than k values.
+func (s DomainSet[T]) Select(k uint64) (T, bool) {
+	return domain_select(s.Domain, s.Set, k)
+}
+
+// RankIndex builds an index for repeated Rank and Select queries.
+func (s DomainSet[T]) RankIndex() *RankIndex[T] {
+	return new_rank_index(s.Domain, s.Set)
+}
@@ -0,0 +1,88 @@
+package ics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"unicode"
+)
+
+func TestRankSelect_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 300; i++ {
+		s := random_int8set(r)
+		x := NewRankIndex(s)
+
+		var members []int8
+		for v := math.MinInt8; v <= math.MaxInt8; v++ {
+			if got := Rank(s, int8(v)); got != uint64(len(members)) || x.Rank(int8(v)) != got {
+				t.Fatalf("Rank(%v, %d) = %d, index %d, want %d", s, v, got, x.Rank(int8(v)), len(members))
+			}
+			if s.Contains(int8(v)) {
+				members = append(members, int8(v))
+			}
+		}
+		if x.Count() != uint64(len(members)) {
+			t.Fatalf("%v.Count() = %d, want %d", s, x.Count(), len(members))
+		}
+		for k := 0; k <= len(members); k++ {
+			v, ok := Select(s, uint64(k))
+			xv, xok := x.Select(uint64(k))
+			if k == len(members) {
+				if ok || xok {
+					t.Fatalf("Select(%v, %d) = %d, index %d, want none", s, k, v, xv)
+				}
+			} else if !ok || !xok || v != members[k] || xv != members[k] {
+				t.Fatalf("Select(%v, %d) = %d, index %d, want %d", s, k, v, xv, members[k])
+			}
+		}
+	}
+}
+
+func TestRuneSet_Rank(t *testing.T) {
+	s := FromRangeTable(unicode.Greek)
+	x := s.RankIndex()
+	if x.Count() != uint64(s.CountElements()) {
+		t.Errorf("Count() = %d, want %d", x.Count(), s.CountElements())
+	}
+	k := uint64(0)
+	for r := range s.Elements() {
+		if got := s.Rank(r); got != k || x.Rank(r) != k {
+			t.Fatalf("Rank(%q) = %d, index %d, want %d", r, got, x.Rank(r), k)
+		}
+		if got, ok := s.Select(k); got != r || !ok {
+			t.Fatalf("Select(%d) = %q, %v, want %q", k, got, ok, r)
+		}
+		if got, ok := x.Select(k); got != r || !ok {
+			t.Fatalf("index Select(%d) = %q, %v, want %q", k, got, ok, r)
+		}
+		k++
+	}
+
+	open := RuneSet{'a'}
+	if got := open.Rank(unicode.MaxRune + 1); got != 0x110000-'a' {
+		t.Errorf("Rank beyond MaxRune = %d", got)
+	}
+	if r, ok := open.Select(0x110000 - 'a' - 1); r != unicode.MaxRune || !ok {
+		t.Errorf("Select of the last rune = %#x, %v", r, ok)
+	}
+	if r, ok := open.RankIndex().Select(0x110000 - 'a'); ok {
+		t.Errorf("Select beyond the last rune = %#x", r)
+	}
+
+	a := AsciiSet{'0', '9' + 1, 'a', 'f' + 1}
+	if c, ok := a.Select(12); c != 'c' || !ok {
+		t.Errorf("AsciiSet.Select(12) = %q, %v", c, ok)
+	}
+	if got := a.RankIndex().Rank('z'); got != 16 {
+		t.Errorf("AsciiSet.Rank('z') = %d", got)
+	}
+
+	full := DomainSet[uint64]{Uint64Domain, Set[uint64]{0}}
+	if got, ok := full.Select(math.MaxUint64); got != math.MaxUint64 || !ok {
+		t.Errorf("Select(MaxUint64) = %d, %v", got, ok)
+	}
+	if got := full.RankIndex().Rank(math.MaxUint64); got != math.MaxUint64 {
+		t.Errorf("Rank(MaxUint64) = %d", got)
+	}
+}
@@ -0,0 +1,244 @@
+package ics
+
+import (
+	"unicode/utf8"
+)
+
+// The scanning helpers below classify ASCII bytes with a bitmap built from the
+// ASCII half of the set, and only decode multi-byte UTF-8 sequences. Invalid UTF-8 is processed one byte at a time, each invalid byte
+// is classified as utf8.RuneError (U+FFFD), which is consistent with ranging
+// over a string.
+
+// IndexIn returns the byte index of the first rune of b contained in s, or -1
+// if there is none.
+func (s RuneSet) IndexIn(b []byte) int {
+	sc := s.scanner()
+	return scan_index(&sc, b, true)
+}
+
+// IndexInString returns the byte index of the first rune of str contained in
+// s, or -1 if there is none.
+func (s RuneSet) IndexInString(str string) int {
+	sc := s.scanner()
+	return scan_index(&sc, str, true)
+}
+
+// IndexNotIn returns the byte index of the first rune of b not contained in s,
+// or -1 if there is none.
+func (s RuneSet) IndexNotIn(b []byte) int {
+	sc := s.scanner()
+	return scan_index(&sc, b, false)
+}
+
+// IndexNotInString returns the byte index of the first rune of str not
+// contained in s, or -1 if there is none.
+func (s RuneSet) IndexNotInString(str string) int {
+	sc := s.scanner()
+	return scan_index(&sc, str, false)
+}
+
+// LastIndexIn returns the byte index of the last rune of b contained in s, or
+// -1 if there is none.
+func (s RuneSet) LastIndexIn(b []byte) int {
+	sc := s.scanner()
+	i, _ := scan_last_index(&sc, b, 0, true)
+	return i
+}
+
+// LastIndexInString returns the byte index of the last rune of str contained
+// in s, or -1 if there is none.
+func (s RuneSet) LastIndexInString(str string) int {
+	sc := s.scanner()
+	i, _ := scan_last_index(&sc, str, 0, true)
+	return i
+}
+
+// SpanIn returns the length in bytes of the leading run of runes of b that are
+// contained in s.
+func (s RuneSet) SpanIn(b []byte) int {
+	sc := s.scanner()
+	return scan_span(&sc, b)
+}
+
+// SpanInString returns the length in bytes of the leading run of runes of str
+// that are contained in s.
+func (s RuneSet) SpanInString(str string) int {
+	sc := s.scanner()
+	return scan_span(&sc, str)
+}
+
+// TrimIn returns a subslice of b with all leading and trailing runes contained
+// in s removed.
+func (s RuneSet) TrimIn(b []byte) []byte {
+	sc := s.scanner()
+	return scan_trim(&sc, b)
+}
+
+// TrimInString returns a substring of str with all leading and trailing runes
+// contained in s removed.
+func (s RuneSet) TrimInString(str string) string {
+	sc := s.scanner()
+	return scan_trim(&sc, str)
+}
+
+// FieldsIn splits b around each run of consecutive runes contained in s.
+// Returns an empty list if b consists of such runes only.
+func (s RuneSet) FieldsIn(b []byte) [][]byte {
+	sc := s.scanner()
+	return scan_fields(&sc, b)
+}
+
+// FieldsInString splits str around each run of consecutive runes contained in
+// s. Returns an empty list if str consists of such runes only.
+func (s RuneSet) FieldsInString(str string) []string {
+	sc := s.scanner()
+	return scan_fields(&sc, str)
+}
+
+// SplitIn splits b around each rune contained in s. Unlike FieldsIn, adjacent
+// separators produce empty subslices, so the result has one more element than
+// there are separators.
+func (s RuneSet) SplitIn(b []byte) [][]byte {
+	sc := s.scanner()
+	return scan_split(&sc, b)
+}
+
+// SplitInString splits str around each rune contained in s. Unlike
+// FieldsInString, adjacent separators produce empty substrings, so the result
+// has one more element than there are separators.
+func (s RuneSet) SplitInString(str string) []string {
+	sc := s.scanner()
+	return scan_split(&sc, str)
+}
+
+// ContainsAllRunes indicates if all runes of b are contained in s.
+func (s RuneSet) ContainsAllRunes(b []byte) bool {
+	sc := s.scanner()
+	return scan_index(&sc, b, false) < 0
+}
+
+// ContainsAllRunesString indicates if all runes of str are contained in s.
+func (s RuneSet) ContainsAllRunesString(str string) bool {
+	sc := s.scanner()
+	return scan_index(&sc, str, false) < 0
+}
+
+type rune_scanner struct {
+	ascii AsciiBitmap
+	set   RuneSet
+	base  int // number of boundaries of set below utf8.RuneSelf
+}
+
+// scanner prepares s for scanning. The ASCII ranges of s are copied into a
+// bitmap, the runes above the ASCII range are looked up in s in place, so that
+// the scanner does not allocate.
+func (s RuneSet) scanner() rune_scanner {
+	sc := rune_scanner{set: s}
+	sc.base, _ = search(s, utf8.RuneSelf)
+	for i := 0; i < sc.base; i += 2 {
+		cmax := utf8.RuneSelf - 1
+		if i+1 < len(s) {
+			cmax = min(cmax, int(s[i+1])-1)
+		}
+		sc.ascii.set_range(int(s[i]), cmax)
+	}
+	return sc
+}
+
+// upper indicates if r, which is above the ASCII range, is contained in the
+// set.
+func (sc *rune_scanner) upper(r rune) bool {
+	return (sc.base+count_le(sc.set[sc.base:], r))&1 != 0
+}
+
+// scan_next classifies the rune that starts at s[i] and returns its size.
+func scan_next[S []byte | string](sc *rune_scanner, s S, i int) (bool, int) {
+	if c := s[i]; c < utf8.RuneSelf {
+		return sc.ascii.bit(c) != 0, 1
+	}
+	// short conversions of []byte to string do not allocate
+	r, n := utf8.DecodeRuneInString(string(s[i:min(i+utf8.UTFMax, len(s))]))
+	return sc.upper(r), n
+}
+
+// scan_prev classifies the rune that ends at s[j-1] and returns its size.
+func scan_prev[S []byte | string](sc *rune_scanner, s S, j int) (bool, int) {
+	if c := s[j-1]; c < utf8.RuneSelf {
+		return sc.ascii.bit(c) != 0, 1
+	}
+	r, n := utf8.DecodeLastRuneInString(string(s[max(j-utf8.UTFMax, 0):j]))
+	return sc.upper(r), n
+}
+
+func scan_index[S []byte | string](sc *rune_scanner, s S, want bool) int {
+	for i := 0; i < len(s); {
+		in, n := scan_next(sc, s, i)
+		if in == want {
+			return i
+		}
+		i += n
+	}
+	return -1
+}
+
+// scan_last_index returns the index and the size of the last rune within
+// s[lo:] that is (want = true) or is not (want = false) contained in the set.
+func scan_last_index[S []byte | string](sc *rune_scanner, s S, lo int, want bool) (int, int) {
+	for j := len(s); j > lo; {
+		in, n := scan_prev(sc, s, j)
+		j -= n
+		if in == want {
+			return j, n
+		}
+	}
+	return -1, 0
+}
+
+func scan_span[S []byte | string](sc *rune_scanner, s S) int {
+	if i := scan_index(sc, s, false); i >= 0 {
+		return i
+	}
+	return len(s)
+}
+
+func scan_trim[S []byte | string](sc *rune_scanner, s S) S {
+	l := scan_span(sc, s)
+	h := l
+	if i, n := scan_last_index(sc, s, l, false); i >= 0 {
+		h = i + n
+	}
+	return s[l:h]
+}
+
+func scan_fields[S []byte | string](sc *rune_scanner, s S) []S {
+	var r []S
+	start := -1
+	for i := 0; i < len(s); {
+		in, n := scan_next(sc, s, i)
+		if in && start >= 0 {
+			r = append(r, s[start:i])
+			start = -1
+		} else if !in && start < 0 {
+			start = i
+		}
+		i += n
+	}
+	if start >= 0 {
+		r = append(r, s[start:])
+	}
+	return r
+}
+
+func scan_split[S []byte | string](sc *rune_scanner, s S) []S {
+	var r []S
+	start := 0
+	for i := 0; i < len(s); {
+		in, n := scan_next(sc, s, i)
+		if in {
+			r = append(r, s[start:i])
+			start = i + n
+		}
+		i += n
+	}
+	return append(r, s[start:])
+}
@@ -0,0 +1,196 @@
+package ics
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestRuneSet_Scan(t *testing.T) {
+	space := FromRangeTable(unicode.White_Space)
+	tests := []struct {
+		s      RuneSet
+		str    string
+		fields []string
+		split  []string
+		trim   string
+	}{
+		{space, "", nil, []string{""}, ""},
+		{space, " \t", nil, []string{"", "", ""}, ""},
+		{space, " a  b　c ", []string{"a", "b", "c"}, []string{"", "a", "", "b", "c", ""}, "a  b　c"},
+		{space, "αβ γ", []string{"αβ", "γ"}, []string{"αβ", "γ"}, "αβ γ"},
+		{RuneSet{unicode.ReplacementChar, unicode.ReplacementChar + 1}, "a\xffb\xe2\x82", []string{"a", "b"}, []string{"a", "b", "", ""}, "a\xffb"},
+	}
+	for _, tt := range tests {
+		if got := tt.s.FieldsInString(tt.str); !slices.Equal(got, tt.fields) {
+			t.Errorf("FieldsInString(%q) = %q, want %q", tt.str, got, tt.fields)
+		}
+		if got := tt.s.SplitInString(tt.str); !slices.Equal(got, tt.split) {
+			t.Errorf("SplitInString(%q) = %q, want %q", tt.str, got, tt.split)
+		}
+		if got := tt.s.TrimInString(tt.str); got != tt.trim {
+			t.Errorf("TrimInString(%q) = %q, want %q", tt.str, got, tt.trim)
+		}
+	}
+}
+
+func random_utf8(s RuneSet, n int) string {
+	w := strings.Builder{}
+	for i := 0; i < n; i++ {
+		switch rand.Intn(8) {
+		case 0:
+			// invalid or truncated sequences
+			w.WriteString([]string{"\xff", "\x80", "\xe2\x82", "\xed\xa0\x80"}[rand.Intn(4)])
+		case 1, 2, 3:
+			if len(s) > 0 {
+				r := s[rand.Intn(len(s))]
+				if r <= unicode.MaxRune {
+					w.WriteRune(r)
+				}
+				continue
+			}
+			fallthrough
+		default:
+			w.WriteRune([]rune{'a', ' ', '-', 'é', 'α', '€', '😀'}[rand.Intn(7)])
+		}
+	}
+	return w.String()
+}
+
+// split_func splits str around each rune that satisfies f.
+func split_func(str string, f func(rune) bool) []string {
+	r := []string{}
+	start := 0
+	for i := 0; i < len(str); {
+		c, n := utf8.DecodeRuneInString(str[i:])
+		if f(c) {
+			r = append(r, str[start:i])
+			start = i + n
+		}
+		i += n
+	}
+	return append(r, str[start:])
+}
+
+// equal_fields compares the byte slices of b with the strings of s element
+// by element.
+func equal_fields(b [][]byte, s []string) bool {
+	if len(b) != len(s) {
+		return false
+	}
+	for i := range b {
+		if string(b[i]) != s[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRuneSet_Scan_Random(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		s := random_runeset(rand.Intn(8))
+		if rand.Intn(4) == 0 {
+			s = Union(s, RuneSet{unicode.ReplacementChar, unicode.ReplacementChar + 1})
+		}
+		str := random_utf8(s, rand.Intn(12))
+		b := []byte(str)
+		not_in := func(r rune) bool {
+			return !s.Contains(r)
+		}
+
+		if got, want := s.IndexInString(str), strings.IndexFunc(str, s.Contains); got != want || s.IndexIn(b) != want {
+			t.Fatalf("%v.IndexIn(%q) = %d, want %d", s, str, got, want)
+		}
+		if got, want := s.IndexNotInString(str), strings.IndexFunc(str, not_in); got != want || s.IndexNotIn(b) != want {
+			t.Fatalf("%v.IndexNotIn(%q) = %d, want %d", s, str, got, want)
+		}
+		if got, want := s.LastIndexInString(str), strings.LastIndexFunc(str, s.Contains); got != want || s.LastIndexIn(b) != want {
+			t.Fatalf("%v.LastIndexIn(%q) = %d, want %d", s, str, got, want)
+		}
+		span := len(str) - len(strings.TrimLeftFunc(str, s.Contains))
+		if got := s.SpanInString(str); got != span || s.SpanIn(b) != span {
+			t.Fatalf("%v.SpanIn(%q) = %d, want %d", s, str, got, span)
+		}
+		if got, want := s.TrimInString(str), strings.TrimFunc(str, s.Contains); got != want || !bytes.Equal(s.TrimIn(b), []byte(want)) {
+			t.Fatalf("%v.TrimIn(%q) = %q, want %q", s, str, got, want)
+		}
+		fields := strings.FieldsFunc(str, s.Contains)
+		if got := s.FieldsInString(str); !slices.Equal(got, fields) && len(got)+len(fields) > 0 {
+			t.Fatalf("%v.FieldsIn(%q) = %q, want %q", s, str, got, fields)
+		}
+		if got := s.FieldsIn(b); !equal_fields(got, fields) {
+			t.Fatalf("%v.FieldsIn(%q) = %q, want %q", s, str, got, fields)
+		}
+		if got, want := s.ContainsAllRunesString(str), strings.IndexFunc(str, not_in) < 0; got != want || s.ContainsAllRunes(b) != want {
+			t.Fatalf("%v.ContainsAllRunes(%q) = %v, want %v", s, str, got, want)
+		}
+
+		split := split_func(str, s.Contains)
+		if got := s.SplitInString(str); !slices.Equal(got, split) {
+			t.Fatalf("%v.SplitIn(%q) = %q, want %q", s, str, got, split)
+		}
+		if got := s.SplitIn(b); !equal_fields(got, split) {
+			t.Fatalf("%v.SplitIn(%q) = %q, want %q", s, str, got, split)
+		}
+	}
+}
+
+func BenchmarkRuneSet_IndexNotIn(b *testing.B) {
+	s := FromRangeTable(unicode.L)
+	text := strings.Repeat("identifier", 8) + "ünïcödé "
+	b.Run("IndexFunc", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			strings.IndexFunc(text, func(r rune) bool { return !s.Contains(r) })
+		}
+	})
+	b.Run("IndexNotIn", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s.IndexNotInString(text)
+		}
+	})
+}
+
+func BenchmarkRuneSet_SpanIn(b *testing.B) {
+	s := FromRangeTable(unicode.L)
+	text := []byte("ünïcödé identifier")
+	b.Run("SpanIn", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			s.SpanIn(text)
+		}
+	})
+	b.Run("SpanInString", func(b *testing.B) {
+		b.ReportAllocs()
+		str := string(text)
+		for i := 0; i < b.N; i++ {
+			s.SpanInString(str)
+		}
+	})
+	b.Run("TrimInString", func(b *testing.B) {
+		b.ReportAllocs()
+		str := string(text)
+		for i := 0; i < b.N; i++ {
+			s.TrimInString(str)
+		}
+	})
+}
+
+func TestRuneSet_ScanAllocs(t *testing.T) {
+	s := FromRangeTable(unicode.L)
+	str := "ünïcödé identifier"
+	if n := testing.AllocsPerRun(100, func() {
+		s.IndexNotInString(str)
+		s.SpanInString(str)
+		s.TrimInString(str)
+		s.ContainsAllRunesString(str)
+	}); n != 0 {
+		t.Errorf("RuneSet scanning allocates %v times per run, want 0", n)
+	}
+}
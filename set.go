@@ -1,7 +1,10 @@
 package ics
 
 import (
+	"strings"
+
 	"golang.org/x/exp/constraints"
+	"golang.org/x/exp/slices"
 )
 
 // Set is a compacted and flattened representation of a set of arithmetic
@@ -20,13 +23,7 @@ type Set[T constraints.Ordered] []T
 // Contains returns true if element e passes containment test within the
 // interval set s.
 func Contains[S ~[]T, T constraints.Ordered](s S, e T) bool {
-	var i int
-	var ok bool
-	if len(s) < linear_search_threshold {
-		i, ok = linear_search(s, e)
-	} else {
-		i, ok = binary_search(s, e)
-	}
+	i, ok := search(s, e)
 	return (i&1 == 0) == ok
 }
 
@@ -43,8 +40,100 @@ func Hull[S ~[]T, T constraints.Ordered](s S) S {
 	}
 }
 
+// Contains indicates if e is contained within s.
+func (s Set[T]) Contains(e T) bool {
+	return Contains(s, e)
+}
+
+// Insert merges an interval into s. See InsertInterval for details.
+func (s *Set[T]) Insert(l, h T) {
+	InsertInterval(s, l, h)
+}
+
+// Remove carves an interval out of s. See RemoveInterval for details.
+func (s *Set[T]) Remove(l, h T) {
+	RemoveInterval(s, l, h)
+}
+
+// Join fills the gaps between the intervals that overlap with [l,h). See Join
+// for details.
+func (s *Set[T]) Join(l, h T) {
+	Join(s, l, h)
+}
+
+// Hull returns a set that contains at most one interval that covers all
+// intervals in s.
+func (s Set[T]) Hull() Set[T] {
+	return Hull(s)
+}
+
+// Enumerate calls f with half-open boundaries for each interval within the
+// set. See Enumerate for details.
+func (s Set[T]) Enumerate(f func(l, h T)) {
+	Enumerate(s, f)
+}
+
+// Union returns a set that contains all elements contained in s or o.
+func (s Set[T]) Union(o Set[T]) Set[T] {
+	return Union(s, o)
+}
+
+// Intersect returns a set that contains the elements contained in both s and
+// o.
+func (s Set[T]) Intersect(o Set[T]) Set[T] {
+	return Intersect(s, o)
+}
+
+// Difference returns a set that contains the elements contained in s, but not
+// in o.
+func (s Set[T]) Difference(o Set[T]) Set[T] {
+	return Difference(s, o)
+}
+
+// SymmetricDifference returns a set that contains the elements contained in
+// either s or o, but not in both.
+func (s Set[T]) SymmetricDifference(o Set[T]) Set[T] {
+	return SymmetricDifference(s, o)
+}
+
+// Len returns the number of intervals in s, including the open-ended one.
+func (s Set[T]) Len() int {
+	return (len(s) + 1) / 2
+}
+
+// IsEmpty indicates if s contains no elements.
+func (s Set[T]) IsEmpty() bool {
+	return len(s) == 0
+}
+
+// Equal indicates if s and o contain the same elements.
+func (s Set[T]) Equal(o Set[T]) bool {
+	return slices.Equal(s, o)
+}
+
+// Clone returns a copy of s.
+func (s Set[T]) Clone() Set[T] {
+	return slices.Clone(s)
+}
+
+// String produces a human-readable string with half-open intervals.
+func (s Set[T]) String() string {
+	w := strings.Builder{}
+	Write(&w, s)
+	return w.String()
+}
+
 const linear_search_threshold = 64
 
+// search picks between linear_search and binary_search depending on the
+// number of elements in s.
+func search[S ~[]T, T constraints.Ordered](s S, e T) (int, bool) {
+	if len(s) < linear_search_threshold {
+		return linear_search(s, e)
+	}
+	return binary_search(s, e)
+}
+
 func linear_search[S ~[]T, T constraints.Ordered](s S, e T) (int, bool) {
 	i, n := 0, len(s)
 	for i < n && s[i] < e {
@@ -0,0 +1,374 @@
+package ics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/exp/constraints"
+)
+
+// MarshalText implements encoding.TextMarshaler. The text form is the same as
+// the one produced by String, e.g. [1,5)[8..., except that string elements are
+// quoted.
+func (s Set[T]) MarshalText() ([]byte, error) {
+	b := []byte{}
+	i, n := 0, len(s)
+	for i+1 < n {
+		b = append(b, '[')
+		b = append_text_value(b, s[i])
+		b = append(b, ',')
+		b = append_text_value(b, s[i+1])
+		b = append(b, ')')
+		i += 2
+	}
+	if i < n {
+		b = append(b, '[')
+		b = append_text_value(b, s[i])
+		b = append(b, "..."...)
+	}
+	return b, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the form
+// produced by MarshalText, the intervals may appear in any order and they may
+// overlap. The open-ended interval can be written either as [8... or as
+// [8,...).
+func (s *Set[T]) UnmarshalText(text []byte) error {
+	src := string(text)
+	b := Builder[T]{}
+	for pos := 0; pos < len(src); {
+		start := pos
+		if src[pos] != '[' {
+			return &ParseError{Offset: pos, Msg: "missing opening ["}
+		}
+		pos++
+		l, n, err := parse_text_value[T](src[pos:])
+		if err != nil {
+			return &ParseError{Offset: pos, Msg: err.Error()}
+		}
+		pos += n
+
+		if strings.HasPrefix(src[pos:], "...") {
+			b.Add(l, l)
+			pos += 3
+			continue
+		}
+		if !strings.HasPrefix(src[pos:], ",") {
+			return &ParseError{Offset: pos, Msg: "missing comma"}
+		}
+		pos++
+		if strings.HasPrefix(src[pos:], "...)") {
+			b.Add(l, l)
+			pos += 4
+			continue
+		}
+		h, n, err := parse_text_value[T](src[pos:])
+		if err != nil {
+			return &ParseError{Offset: pos, Msg: err.Error()}
+		}
+		pos += n
+		if !strings.HasPrefix(src[pos:], ")") {
+			return &ParseError{Offset: pos, Msg: "missing closing )"}
+		}
+		pos++
+		if h <= l {
+			return &ParseError{Offset: start, Msg: "invalid interval"}
+		}
+		b.Add(l, h)
+	}
+	*s = b.Build()
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The set is written as a JSON string
+// in the text form, see MarshalText.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	text, err := s.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Besides the string produced by
+// MarshalJSON, it accepts an array of intervals, where each interval is either
+// a two-element [l,h] array or a one-element [l] array for the open-ended
+// interval [l,..., e.g. [[1,5],[8]].
+//
+// For integer element types, the [l,h] pair is the inclusive range of values
+// from l to h, same as with RuneSet and AsciiSet, so [[1,5],[8]] decodes to
+// [1,6)[8.... A pair that ends with the largest value of T is open-ended. For
+// floating point and string element types, where the next value is not
+// defined, the pair is the half-open interval [l,h) and l must be less than h.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case bytes.HasPrefix(data, []byte(`"`)):
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return s.UnmarshalText([]byte(text))
+	}
+
+	var items [][]T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("ics: expected a string or an array of intervals: %w", err)
+	}
+	discrete := is_integer[T]()
+	b := Builder[T]{}
+	for i, v := range items {
+		switch {
+		case len(v) == 1:
+			b.Add(v[0], v[0])
+		case len(v) == 2 && discrete && v[0] <= v[1]:
+			// h+1 wraps around to the smallest value for the largest h,
+			// which Add treats as open-ended
+			b.Add(v[0], next_integer(v[1]))
+		case len(v) == 2 && !discrete && v[0] < v[1]:
+			b.Add(v[0], v[1])
+		default:
+			return fmt.Errorf("ics: invalid interval %v at index %d", v, i)
+		}
+	}
+	*s = b.Build()
+	return nil
+}
+
+// is_integer indicates if T is an integer type.
+func is_integer[T constraints.Ordered]() bool {
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.String, reflect.Float32, reflect.Float64:
+		return false
+	}
+	return true
+}
+
+// next_integer returns v+1 for an integer v, wrapping around on overflow.
+func next_integer[T constraints.Ordered](v T) T {
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		rv.SetInt(rv.Int() + 1)
+	default:
+		rv.SetUint(rv.Uint() + 1)
+	}
+	return v
+}
+
+// append_text_value appends the text form of v to b.
+func append_text_value[T constraints.Ordered](b []byte, v T) []byte {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return strconv.AppendQuote(b, rv.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(b, rv.Int(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(b, rv.Float(), 'g', -1, rv.Type().Bits())
+	default:
+		return strconv.AppendUint(b, rv.Uint(), 10)
+	}
+}
+
+// parse_text_value parses the text form of a value at the start of src.
+// Returns the value and the number of consumed bytes.
+func parse_text_value[T constraints.Ordered](src string) (T, int, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() == reflect.String {
+		q, err := strconv.QuotedPrefix(src)
+		if err != nil {
+			return v, 0, fmt.Errorf("invalid quoted string")
+		}
+		str, _ := strconv.Unquote(q)
+		rv.SetString(str)
+		return v, len(q), nil
+	}
+
+	n := strings.IndexAny(src, ",)")
+	if i := strings.Index(src, "..."); i >= 0 && (n < 0 || i < n) {
+		n = i
+	}
+	if n < 0 {
+		n = len(src)
+	}
+	tok := src[:n]
+	var err error
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(tok, 10, rv.Type().Bits()); err == nil {
+			rv.SetInt(i)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(tok, rv.Type().Bits()); err == nil {
+			if math.IsNaN(f) {
+				return v, 0, fmt.Errorf("unsupported value %q", tok)
+			}
+			rv.SetFloat(f)
+		}
+	default:
+		var u uint64
+		if u, err = strconv.ParseUint(tok, 10, rv.Type().Bits()); err == nil {
+			rv.SetUint(u)
+		}
+	}
+	if err != nil {
+		return v, 0, fmt.Errorf("invalid value %q", tok)
+	}
+	return v, n, nil
+}
+
+// MarshalText implements encoding.TextMarshaler. The text form is the same as
+// the one produced by String, e.g. a-z0-9_.
+func (s RuneSet) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, the text is parsed with
+// ParseRuneSet in the Plain dialect.
+func (s *RuneSet) UnmarshalText(text []byte) error {
+	r, err := ParseRuneSet(string(text), Plain)
+	if err != nil {
+		return err
+	}
+	*s = r
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The set is written as a JSON string
+// in the text form, see MarshalText.
+func (s RuneSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Besides the string produced by
+// MarshalJSON, it accepts an array of runes and inclusive [rmin,rmax] ranges
+// of runes, see Set.UnmarshalJSON. Each rune is written either as a number or as a single-character
+// string, e.g. [["a","z"],["0","9"],"_",[128,255]].
+func (s *RuneSet) UnmarshalJSON(data []byte) error {
+	r, err := unmarshal_json_class(data, utf8.MaxRune)
+	if err == nil && r != nil {
+		*s = r
+	}
+	return err
+}
+
+// MarshalText implements encoding.TextMarshaler. The text form is the same as
+// the one produced by String, e.g. a-z0-9_.
+func (s AsciiSet) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, the text is parsed with
+// ParseAsciiSet in the Plain dialect.
+func (s *AsciiSet) UnmarshalText(text []byte) error {
+	a, err := ParseAsciiSet(string(text), Plain)
+	if err != nil {
+		return err
+	}
+	*s = a
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The set is written as a JSON string
+// in the text form, see MarshalText.
+func (s AsciiSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts the same forms as
+// RuneSet.UnmarshalJSON, restricted to ASCII characters.
+func (s *AsciiSet) UnmarshalJSON(data []byte) error {
+	r, err := unmarshal_json_class(data, 0x7f)
+	if err == nil && r != nil {
+		*s, _ = r.AsciiSplit()
+	}
+	return err
+}
+
+// unmarshal_json_class decodes the JSON forms of rune and ascii sets with
+// characters up to max. Returns a nil set for a JSON null.
+func unmarshal_json_class(data []byte, max rune) (RuneSet, error) {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil, nil
+	case bytes.HasPrefix(data, []byte(`"`)):
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return nil, err
+		}
+		r, err := parse_class(text, Plain, max)
+		if err == nil && r == nil {
+			r = RuneSet{}
+		}
+		return r, err
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("ics: expected a string or an array of ranges: %w", err)
+	}
+	b := RuneSetBuilder{}
+	for i, item := range items {
+		var lo, hi rune
+		var err error
+		if bytes.HasPrefix(bytes.TrimSpace(item), []byte("[")) {
+			var pair []json.RawMessage
+			if err = json.Unmarshal(item, &pair); err == nil && len(pair) != 2 {
+				err = fmt.Errorf("expected a two-element array")
+			}
+			if err == nil {
+				lo, err = unmarshal_json_rune(pair[0], max)
+			}
+			if err == nil {
+				hi, err = unmarshal_json_rune(pair[1], max)
+			}
+			if err == nil && hi < lo {
+				err = fmt.Errorf("invalid range")
+			}
+		} else {
+			lo, err = unmarshal_json_rune(item, max)
+			hi = lo
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ics: %v at index %d", err, i)
+		}
+		b.AddRange(lo, hi)
+	}
+	return b.Build(), nil
+}
+
+// unmarshal_json_rune decodes a character written either as a number or as a
+// single-character string.
+func unmarshal_json_rune(data json.RawMessage, max rune) (rune, error) {
+	var r rune
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return 0, err
+		}
+		var n int
+		r, n = utf8.DecodeRuneInString(str)
+		if n == 0 || n != len(str) {
+			return 0, fmt.Errorf("expected a single character, got %q", str)
+		}
+	} else if err := json.Unmarshal(data, &r); err != nil {
+		return 0, err
+	}
+	if r < 0 || r > max {
+		return 0, fmt.Errorf("character %d is out of range", r)
+	}
+	return r, nil
+}
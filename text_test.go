@@ -0,0 +1,199 @@
+package ics
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSet_MarshalText(t *testing.T) {
+	tests := []struct {
+		s    Set[int]
+		text string
+	}{
+		{Set[int]{}, ``},
+		{Set[int]{-3}, `[-3...`},
+		{Set[int]{1, 5, 8}, `[1,5)[8...`},
+		{Set[int]{math.MinInt, 0, 1, math.MaxInt}, `[-9223372036854775808,0)[1,9223372036854775807)`},
+	}
+	for _, tt := range tests {
+		b, err := tt.s.MarshalText()
+		if err != nil || string(b) != tt.text || tt.s.String() != tt.text {
+			t.Errorf("%v.MarshalText() = %q, %v, want %q", tt.s, b, err, tt.text)
+		}
+		var got Set[int]
+		if err := got.UnmarshalText(b); err != nil || !got.Equal(tt.s) {
+			t.Errorf("UnmarshalText(%q) = %v, %v, want %v", b, got, err, tt.s)
+		}
+	}
+
+	f := Set[float32]{float32(math.Inf(-1)), -0.1, 1e-45, 3.4028235e+38}
+	b, _ := f.MarshalText()
+	var fgot Set[float32]
+	if err := fgot.UnmarshalText(b); err != nil || !fgot.Equal(f) {
+		t.Errorf("UnmarshalText(%q) = %v, %v, want %v", b, fgot, err, f)
+	}
+
+	s := Set[string]{`"[)`, "a,b", "a..."}
+	if b, _ = s.MarshalText(); string(b) != `["\"[)","a,b")["a..."...` {
+		t.Errorf("%v.MarshalText() = %q", s, b)
+	}
+	var sgot Set[string]
+	if err := sgot.UnmarshalText(b); err != nil || !sgot.Equal(s) {
+		t.Errorf("UnmarshalText(%q) = %v, %v, want %v", b, sgot, err, s)
+	}
+}
+
+func TestSet_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text string
+		want Set[int8]
+		ok   bool
+	}{
+		{`[8,...)`, Set[int8]{8}, true},
+		{`[8,10)[1,5)[4,9)`, Set[int8]{1, 10}, true},
+		{`[8...[1,3)[9,12)`, Set[int8]{1, 3, 8}, true},
+		{`[1,5`, nil, false},
+		{`[1;5)`, nil, false},
+		{`1,5)`, nil, false},
+		{`[5,5)`, nil, false},
+		{`[5,1)`, nil, false},
+		{`[1,128)`, nil, false},
+		{`[1,5)x`, nil, false},
+		{`[a,5)`, nil, false},
+	}
+	for _, tt := range tests {
+		var got Set[int8]
+		err := got.UnmarshalText([]byte(tt.text))
+		if (err == nil) != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("UnmarshalText(%q) = %v, %v, want %v", tt.text, got, err, tt.want)
+		}
+	}
+}
+
+func TestSet_MarshalJSON(t *testing.T) {
+	type config struct {
+		Ports Set[uint16] `json:"ports"`
+	}
+	c := config{Set[uint16]{80, 81, 8000}}
+	b, err := json.Marshal(c)
+	if err != nil || string(b) != `{"ports":"[80,81)[8000..."}` {
+		t.Errorf("json.Marshal(%v) = %s, %v", c, b, err)
+	}
+
+	tests := []struct {
+		data string
+		want Set[uint16]
+		ok   bool
+	}{
+		{`{"ports":"[80,81)[8000..."}`, Set[uint16]{80, 81, 8000}, true},
+		{`{"ports":[[8000], [80, 80]]}`, Set[uint16]{80, 81, 8000}, true},
+		{`{"ports":[[80, 90], [8000, 65535]]}`, Set[uint16]{80, 91, 8000}, true},
+		{`{"ports":[]}`, Set[uint16]{}, true},
+		{`{"ports":null}`, nil, true},
+		{`{"ports":[[81, 80]]}`, nil, false},
+		{`{"ports":[[1, 2, 3]]}`, nil, false},
+		{`{"ports":[[-1, 2]]}`, nil, false},
+		{`{"ports":42}`, nil, false},
+	}
+	for _, tt := range tests {
+		var got config
+		err := json.Unmarshal([]byte(tt.data), &got)
+		if (err == nil) != tt.ok || !got.Ports.Equal(tt.want) {
+			t.Errorf("json.Unmarshal(%s) = %v, %v, want %v", tt.data, got.Ports, err, tt.want)
+		}
+	}
+}
+
+func TestSet_UnmarshalJSON_Pairs(t *testing.T) {
+	// integer pairs are inclusive, same as for RuneSet
+	data := []byte(`[[48,57],[97,122],[95,95],[945,969]]`)
+	var s Set[int32]
+	var r RuneSet
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	want := RuneSet{'0', '9' + 1, '_', '_' + 1, 'a', 'z' + 1, 'α', 'ω' + 1}
+	if !r.Equal(want) || !s.Equal(Set[int32](want)) {
+		t.Errorf("json.Unmarshal(%s) = %v and %v, want %v", data, s, r, want)
+	}
+
+	var i8 Set[int8]
+	if err := json.Unmarshal([]byte(`[[-128,-128],[127,127]]`), &i8); err != nil || !i8.Equal(Set[int8]{-128, -127, 127}) {
+		t.Errorf("json.Unmarshal(int8 extremes) = %v, %v", i8, err)
+	}
+
+	// floating point pairs are half-open
+	var f Set[float64]
+	if err := json.Unmarshal([]byte(`[[0.5,1.5],[2]]`), &f); err != nil || !f.Equal(Set[float64]{0.5, 1.5, 2}) {
+		t.Errorf("json.Unmarshal(floats) = %v, %v", f, err)
+	}
+	if err := json.Unmarshal([]byte(`[[1,1]]`), &f); err == nil {
+		t.Errorf("json.Unmarshal accepts an empty floating point interval")
+	}
+}
+
+func TestRuneSet_MarshalJSON(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		s := random_runeset(rand.Intn(10))
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got RuneSet
+		if err := json.Unmarshal(b, &got); err != nil || !got.Equal(s) {
+			t.Fatalf("json.Unmarshal(%s) = %v, %v, want %v", b, got, err, s)
+		}
+		text, _ := s.MarshalText()
+		got = nil
+		if err := got.UnmarshalText(text); err != nil || !got.Equal(s) {
+			t.Fatalf("UnmarshalText(%q) = %v, %v, want %v", text, got, err, s)
+		}
+	}
+
+	tests := []struct {
+		data string
+		want RuneSet
+		ok   bool
+	}{
+		{`"a-z0-9_"`, RuneSet{'0', '9' + 1, '_', '_' + 1, 'a', 'z' + 1}, true},
+		{`[["a","z"],["0","9"],"_"]`, RuneSet{'0', '9' + 1, '_', '_' + 1, 'a', 'z' + 1}, true},
+		{`[[1114110,1114111],"α"]`, RuneSet{'α', 'α' + 1, 0x10fffe}, true},
+		{`[]`, RuneSet{}, true},
+		{`[["z","a"]]`, nil, false},
+		{`[["a"]]`, nil, false},
+		{`["ab"]`, nil, false},
+		{`[1114112]`, nil, false},
+		{`[-1]`, nil, false},
+		{`"z-a"`, nil, false},
+	}
+	for _, tt := range tests {
+		var got RuneSet
+		err := json.Unmarshal([]byte(tt.data), &got)
+		if (err == nil) != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("json.Unmarshal(%s) = %v, %v, want %v", tt.data, got, err, tt.want)
+		}
+	}
+}
+
+func TestAsciiSet_MarshalJSON(t *testing.T) {
+	s := AsciiSet{'-', '.', '0', '9' + 1, 'a'}
+	b, err := json.Marshal(s)
+	if err != nil || string(b) != `"\\-0-9a-\\x7F"` {
+		t.Errorf("json.Marshal(%v) = %s, %v", s, b, err)
+	}
+	var got AsciiSet
+	if err := json.Unmarshal(b, &got); err != nil || !got.Equal(s) {
+		t.Errorf("json.Unmarshal(%s) = %v, %v, want %v", b, got, err, s)
+	}
+	if err := json.Unmarshal([]byte(`[["-","-"],["0","9"],["a",127]]`), &got); err != nil || !got.Equal(s) {
+		t.Errorf("json.Unmarshal(array) = %v, %v, want %v", got, err, s)
+	}
+	if err := json.Unmarshal([]byte(`["é"]`), &got); err == nil {
+		t.Errorf("json.Unmarshal accepts non-ascii characters")
+	}
+}
@@ -0,0 +1,190 @@
+// Package ucd builds containment sets from the files of the Unicode Character
+// Database (UCD).
+//
+// The package reads local copies of the UCD files, which makes it possible to
+// use properties that are newer than the tables of the unicode package, as well
+// as properties the unicode package does not provide at all, such as
+// Line_Break or Emoji_Presentation.
+package ucd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/adnsv/ics"
+)
+
+// ParseError describes a problem with the syntax of a UCD file.
+type ParseError struct {
+	Line int    // 1-based line number
+	Msg  string // description of the problem
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("ucd: %s at line %d", e.Msg, e.Line)
+}
+
+// ReadProperties reads a UCD property file and returns a set of codepoints for
+// each property value.
+//
+// This is the format of most UCD files, such as Scripts.txt,
+// DerivedCoreProperties.txt, LineBreak.txt or emoji-data.txt. Each line
+// assigns a value to a single codepoint or to an inclusive range of
+// codepoints:
+//
+//	0041..005A    ; Latin # L&  [26] LATIN CAPITAL LETTER A..LATIN CAPITAL LETTER Z
+//	00AA          ; Latin # Lo       FEMININE ORDINAL INDICATOR
+//
+// Comments and empty lines are ignored. When a line has more than two fields,
+// the value is the remaining fields joined with '=', e.g. the line
+//
+//	0915..0939    ; InCB; Consonant # Lo  [37] DEVANAGARI LETTER KA..DEVANAGARI LETTER HA
+//
+// adds the range to the "InCB=Consonant" set.
+func ReadProperties(r io.Reader) (map[string]ics.RuneSet, error) {
+	builders := map[string]*ics.RuneSetBuilder{}
+	err := scan(r, func(line int, fields []string) error {
+		if len(fields) < 2 || fields[1] == "" {
+			return &ParseError{line, "missing property value"}
+		}
+		lo, hi, err := parse_range(fields[0])
+		if err != nil {
+			return &ParseError{line, err.Error()}
+		}
+		add(builders, strings.Join(fields[1:], "="), lo, hi)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return build(builders), nil
+}
+
+// ReadUnicodeData reads UnicodeData.txt and returns a set of codepoints for
+// each value of the field with the given index, e.g. 2 for General_Category or
+// 4 for Bidi_Class. Codepoints with an empty field are skipped.
+//
+// Large ranges of codepoints are listed in UnicodeData.txt as pairs of lines
+// with the names of the form "<CJK Ideograph, First>" and
+// "<CJK Ideograph, Last>". Such pairs are expanded into ranges.
+func ReadUnicodeData(r io.Reader, field int) (map[string]ics.RuneSet, error) {
+	if field < 1 {
+		return nil, fmt.Errorf("ucd: invalid field index %d", field)
+	}
+
+	builders := map[string]*ics.RuneSetBuilder{}
+	first, first_name, first_line := rune(-1), "", 0
+	err := scan(r, func(line int, fields []string) error {
+		if len(fields) <= field {
+			return &ParseError{line, "missing field " + strconv.Itoa(field)}
+		}
+		c, err := parse_codepoint(fields[0])
+		if err != nil {
+			return &ParseError{line, err.Error()}
+		}
+
+		name := fields[1]
+		lo := c
+		switch {
+		case strings.HasSuffix(name, ", First>"):
+			if first >= 0 {
+				return &ParseError{first_line, "unterminated range"}
+			}
+			first, first_name, first_line = c, strings.TrimSuffix(name, ", First>"), line
+			return nil
+		case strings.HasSuffix(name, ", Last>"):
+			if first < 0 || strings.TrimSuffix(name, ", Last>") != first_name || c < first {
+				return &ParseError{line, "unexpected end of range"}
+			}
+			lo, first = first, -1
+		case first >= 0:
+			return &ParseError{first_line, "unterminated range"}
+		}
+
+		if v := fields[field]; v != "" {
+			add(builders, v, lo, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if first >= 0 {
+		return nil, &ParseError{first_line, "unterminated range"}
+	}
+	return build(builders), nil
+}
+
+// scan calls f with the semicolon-separated fields of each line, comments and
+// surrounding spaces are stripped.
+func scan(r io.Reader, f func(line int, fields []string) error) error {
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		s := sc.Text()
+		if i := strings.IndexByte(s, '#'); i >= 0 {
+			s = s[:i]
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		fields := strings.Split(s, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if err := f(line, fields); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+func parse_codepoint(s string) (rune, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) < 4 {
+		return 0, fmt.Errorf("invalid codepoint %q", s)
+	}
+	if v > utf8.MaxRune {
+		return 0, fmt.Errorf("codepoint %q out of range", s)
+	}
+	return rune(v), nil
+}
+
+// parse_range parses either a single codepoint or an inclusive lo..hi range.
+func parse_range(s string) (lo, hi rune, err error) {
+	l, h, ok := strings.Cut(s, "..")
+	if lo, err = parse_codepoint(l); err != nil {
+		return
+	}
+	if !ok {
+		return lo, lo, nil
+	}
+	if hi, err = parse_codepoint(h); err != nil {
+		return
+	}
+	if hi < lo {
+		err = fmt.Errorf("invalid range %q", s)
+	}
+	return
+}
+
+func add(builders map[string]*ics.RuneSetBuilder, v string, lo, hi rune) {
+	b := builders[v]
+	if b == nil {
+		b = &ics.RuneSetBuilder{}
+		builders[v] = b
+	}
+	b.AddRange(lo, hi)
+}
+
+func build(builders map[string]*ics.RuneSetBuilder) map[string]ics.RuneSet {
+	r := make(map[string]ics.RuneSet, len(builders))
+	for v, b := range builders {
+		r[v] = b.Build()
+	}
+	return r
+}
@@ -0,0 +1,127 @@
+package ucd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/adnsv/ics"
+)
+
+const unicode_data = `0041;LATIN CAPITAL LETTER A;Lu;0;L;;;;;N;;;;0061;
+0042;LATIN CAPITAL LETTER B;Lu;0;L;;;;;N;;;;0062;
+0061;LATIN SMALL LETTER A;Ll;0;L;;;;;N;;;0041;;0041
+0062;LATIN SMALL LETTER B;Ll;0;L;;;;;N;;;0042;;0042
+3400;<CJK Ideograph Extension A, First>;Lo;0;L;;;;;N;;;;;
+4DBF;<CJK Ideograph Extension A, Last>;Lo;0;L;;;;;N;;;;;
+4E00;<CJK Ideograph, First>;Lo;0;L;;;;;N;;;;;
+9FFF;<CJK Ideograph, Last>;Lo;0;L;;;;;N;;;;;
+`
+
+func TestReadUnicodeData(t *testing.T) {
+	got, err := ReadUnicodeData(strings.NewReader(unicode_data), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]ics.RuneSet{
+		"Lu": {'A', 'B' + 1},
+		"Ll": {'a', 'b' + 1},
+		"Lo": {0x3400, 0x4dbf + 1, 0x4e00, 0x9fff + 1},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d values, want %d", len(got), len(want))
+	}
+	for v, s := range want {
+		if !got[v].Equal(s) {
+			t.Errorf("%s = %v, want %v", v, got[v], s)
+		}
+	}
+
+	// simple uppercase mapping is empty for most codepoints
+	got, err = ReadUnicodeData(strings.NewReader(unicode_data), 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || !got["0041"].Equal(ics.RuneSet{'a', 'a' + 1}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestReadUnicodeData_Errors(t *testing.T) {
+	tests := []struct {
+		src  string
+		line int
+	}{
+		{"0041;A;Lu\nZZZZ;B;Lu\n", 2},
+		{"0041;A\n", 1},
+		{"3400;<X, First>;Lo\n0041;A;Lu\n", 1},
+		{"3400;<X, First>;Lo\n", 1},
+		{"4DBF;<X, Last>;Lo\n", 1},
+		{"3400;<X, First>;Lo\n4DBF;<Y, Last>;Lo\n", 2},
+		{"110000;A;Lu\n", 1},
+	}
+	for _, tt := range tests {
+		_, err := ReadUnicodeData(strings.NewReader(tt.src), 2)
+		var e *ParseError
+		if !errors.As(err, &e) {
+			t.Errorf("ReadUnicodeData(%q) = %v, want ParseError", tt.src, err)
+		} else if e.Line != tt.line {
+			t.Errorf("ReadUnicodeData(%q) failed at line %d, want %d", tt.src, e.Line, tt.line)
+		}
+	}
+}
+
+const properties = `# Scripts-15.1.0.txt
+
+# ================================================
+
+0041..005A    ; Latin # L&  [26] LATIN CAPITAL LETTER A..LATIN CAPITAL LETTER Z
+0061..007A    ; Latin # L&  [26] LATIN SMALL LETTER A..LATIN SMALL LETTER Z
+00AA          ; Latin # Lo       FEMININE ORDINAL INDICATOR
+0370..0373    ; Greek # L&   [4] GREEK CAPITAL LETTER HETA..GREEK SMALL LETTER ARCHAIC SAMPI
+1F600..1F64F  ; Emoji_Presentation # E1.0 [80] (😀..🙏) grinning face..folded hands
+0915..0939    ; InCB; Consonant # Lo  [37] DEVANAGARI LETTER KA..DEVANAGARI LETTER HA
+`
+
+func TestReadProperties(t *testing.T) {
+	got, err := ReadProperties(strings.NewReader(properties))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]ics.RuneSet{
+		"Latin":              {'A', 'Z' + 1, 'a', 'z' + 1, 0xaa, 0xaa + 1},
+		"Greek":              {0x370, 0x373 + 1},
+		"Emoji_Presentation": {0x1f600, 0x1f64f + 1},
+		"InCB=Consonant":     {0x915, 0x939 + 1},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d values, want %d", len(got), len(want))
+	}
+	for v, s := range want {
+		if !got[v].Equal(s) {
+			t.Errorf("%s = %v, want %v", v, got[v], s)
+		}
+	}
+}
+
+func TestReadProperties_Errors(t *testing.T) {
+	tests := []struct {
+		src  string
+		line int
+	}{
+		{"0041\n", 1},
+		{"0041;\n", 1},
+		{"\n0041..;Latin\n", 2},
+		{"005A..0041;Latin\n", 1},
+		{"41;Latin\n", 1},
+	}
+	for _, tt := range tests {
+		_, err := ReadProperties(strings.NewReader(tt.src))
+		var e *ParseError
+		if !errors.As(err, &e) {
+			t.Errorf("ReadProperties(%q) = %v, want ParseError", tt.src, err)
+		} else if e.Line != tt.line {
+			t.Errorf("ReadProperties(%q) failed at line %d, want %d", tt.src, e.Line, tt.line)
+		}
+	}
+}
@@ -24,13 +24,7 @@ func (s AsciiSet) Contains(b byte) bool {
 
 // Inverted returns a containment set with inverted logic.
 func (s RuneSet) Inverted() RuneSet {
-	if len(s) == 0 {
-		return RuneSet{0}
-	} else if s[0] == 0 {
-		return s[1:]
-	} else {
-		return append(RuneSet{0}, s...)
-	}
+	return domain_invert(RuneDomain, s)
 }
 
 // Insert adds r to the set.
@@ -38,11 +32,13 @@ func (s *RuneSet) Insert(r rune) {
 	if r < 0 || r > utf8.MaxRune {
 		panic("unsupported rune value")
 	}
-	if r == utf8.MaxRune {
-		InsertInterval(s, r, r)
-	} else {
-		InsertInterval(s, r, r+1)
-	}
+	domain_insert_range(RuneDomain, s, r, r)
+}
+
+// TryInsert is Insert that returns an error wrapping ErrOutOfDomain instead of
+// panicking.
+func (s *RuneSet) TryInsert(r rune) error {
+	return domain_try_insert_range(RuneDomain, s, r, r)
 }
 
 // InsertRange inserts an inclusive [rmin,rmax] range of unicode codepoints into
@@ -55,34 +51,59 @@ func (s *RuneSet) InsertRange(rmin, rmax rune) {
 	if rmin < 0 || rmax > utf8.MaxRune {
 		panic("unsupported rune value")
 	}
-	if rmax == utf8.MaxRune {
-		// insert open-ended interval
-		InsertInterval(s, rmin, rmin)
-	} else {
-		// insert fully-bound interval
-		InsertInterval(s, rmin, rmax+1)
+	domain_insert_range(RuneDomain, s, rmin, rmax)
+}
+
+// TryInsertRange is InsertRange that returns an error wrapping ErrInvalidRange
+// or ErrOutOfDomain instead of panicking.
+func (s *RuneSet) TryInsertRange(rmin, rmax rune) error {
+	return domain_try_insert_range(RuneDomain, s, rmin, rmax)
+}
+
+// Remove removes r from the set.
+func (s *RuneSet) Remove(r rune) {
+	if r < 0 || r > utf8.MaxRune {
+		panic("unsupported rune value")
+	}
+	domain_remove_range(RuneDomain, s, r, r)
+}
+
+// TryRemove is Remove that returns an error wrapping ErrOutOfDomain instead of
+// panicking.
+func (s *RuneSet) TryRemove(r rune) error {
+	return domain_try_remove_range(RuneDomain, s, r, r)
+}
+
+// RemoveRange removes an inclusive [rmin,rmax] range of unicode codepoints from
+// the set.
+func (s *RuneSet) RemoveRange(rmin, rmax rune) {
+	if rmax < rmin {
+		panic("invalid rune range")
+	}
+	if rmin < 0 || rmax > utf8.MaxRune {
+		panic("unsupported rune value")
 	}
+	domain_remove_range(RuneDomain, s, rmin, rmax)
+}
+
+// TryRemoveRange is RemoveRange that returns an error wrapping ErrInvalidRange
+// or ErrOutOfDomain instead of panicking.
+func (s *RuneSet) TryRemoveRange(rmin, rmax rune) error {
+	return domain_try_remove_range(RuneDomain, s, rmin, rmax)
 }
 
 // EnumerateRanges is a functional enumerator for all the continuous inclusive
 // [rmin,rmax] ranges contained within the set.
 func (s RuneSet) EnumerateRanges(f func(rmin, rmax rune)) {
-	i, n := 0, len(s)
-	for i+1 < n {
-		f(s[i], s[i+1]-1)
-		i += 2
-	}
-	if i < n {
-		f(s[i], utf8.MaxRune)
-	}
+	domain_enumerate_ranges(RuneDomain, s, f)
 }
 
 // MergeRuneSets combines multiple containment sets into one.
 func MergeRuneSets(ss ...RuneSet) (m RuneSet) {
+	var tmp RuneSet
 	for _, s := range ss {
-		s.EnumerateRanges(func(rmin, rmax rune) {
-			m.InsertRange(rmin, rmax)
-		})
+		tmp = UnionInto(tmp, m, s)
+		m, tmp = tmp, m
 	}
 	return
 }
@@ -99,6 +120,9 @@ func print_rune(w *bytes.Buffer, r rune) {
 		w.WriteString("\\r")
 	case '\t':
 		w.WriteString("\\t")
+	case '\\', '-':
+		w.WriteByte('\\')
+		w.WriteByte(byte(r))
 	default:
 		if r > unicode.MaxRune {
 			w.WriteString("#INVALID")
@@ -131,7 +155,9 @@ func print_rune(w *bytes.Buffer, r rune) {
 	}
 }
 
-// String produces a human-readable string with ranges and elements.
+// String produces a human-readable string with ranges and elements. Literal
+// backslashes and dashes are escaped, so the result can be parsed back with
+// ParseRuneSet in the Plain dialect.
 func (s RuneSet) String() string {
 	w := bytes.Buffer{}
 	s.EnumerateRanges(func(rmin, rmax rune) {
@@ -187,13 +213,7 @@ func (s RuneSet) AsciiSplit() (AsciiSet, RuneSet) {
 
 // Inverted returns a containment set with inverted logic.
 func (s AsciiSet) Inverted() AsciiSet {
-	if len(s) == 0 {
-		return AsciiSet{0}
-	} else if s[0] == 0 {
-		return s[1:]
-	} else {
-		return append(AsciiSet{0}, s...)
-	}
+	return domain_invert(AsciiDomain, s)
 }
 
 // Insert adds c to the set.
@@ -201,11 +221,13 @@ func (s *AsciiSet) Insert(c byte) {
 	if c > 0x7f {
 		panic("invalid ascii value")
 	}
-	if c == 0x7f {
-		InsertInterval(s, c, c)
-	} else {
-		InsertInterval(s, c, c+1)
-	}
+	domain_insert_range(AsciiDomain, s, c, c)
+}
+
+// TryInsert is Insert that returns an error wrapping ErrOutOfDomain instead of
+// panicking.
+func (s *AsciiSet) TryInsert(c byte) error {
+	return domain_try_insert_range(AsciiDomain, s, c, c)
 }
 
 // InsertRange inserts an inclusive [cmin,cmax] range of ascii characters into
@@ -215,34 +237,56 @@ func (s *AsciiSet) InsertRange(cmin, cmax byte) {
 	if cmax < cmin || cmax > 0x7f {
 		panic("invalid ascii range")
 	}
-	if cmax == 0x7f {
-		// insert open-ended interval
-		InsertInterval(s, cmin, cmin)
-	} else {
-		// insert fully-bound interval
-		InsertInterval(s, cmin, cmax+1)
+	domain_insert_range(AsciiDomain, s, cmin, cmax)
+}
+
+// TryInsertRange is InsertRange that returns an error wrapping ErrInvalidRange
+// or ErrOutOfDomain instead of panicking.
+func (s *AsciiSet) TryInsertRange(cmin, cmax byte) error {
+	return domain_try_insert_range(AsciiDomain, s, cmin, cmax)
+}
+
+// Remove removes c from the set.
+func (s *AsciiSet) Remove(c byte) {
+	if c > 0x7f {
+		panic("invalid ascii value")
 	}
+	domain_remove_range(AsciiDomain, s, c, c)
+}
+
+// TryRemove is Remove that returns an error wrapping ErrOutOfDomain instead of
+// panicking.
+func (s *AsciiSet) TryRemove(c byte) error {
+	return domain_try_remove_range(AsciiDomain, s, c, c)
+}
+
+// RemoveRange removes an inclusive [cmin,cmax] range of ascii characters from
+// the set.
+func (s *AsciiSet) RemoveRange(cmin, cmax byte) {
+	if cmax < cmin || cmax > 0x7f {
+		panic("invalid ascii range")
+	}
+	domain_remove_range(AsciiDomain, s, cmin, cmax)
+}
+
+// TryRemoveRange is RemoveRange that returns an error wrapping ErrInvalidRange
+// or ErrOutOfDomain instead of panicking.
+func (s *AsciiSet) TryRemoveRange(cmin, cmax byte) error {
+	return domain_try_remove_range(AsciiDomain, s, cmin, cmax)
 }
 
 // EnumerateRanges is a functional enumerator for all the continuous inclusive
 // [cmin,cmax] ranges contained within the set.
 func (s AsciiSet) EnumerateRanges(f func(cmin, cmax byte)) {
-	i, n := 0, len(s)
-	for i+1 < n {
-		f(s[i], s[i+1]-1)
-		i += 2
-	}
-	if i < n {
-		f(s[i], 0x7f)
-	}
+	domain_enumerate_ranges(AsciiDomain, s, f)
 }
 
 // MergeRuneSets combines multiple containment sets into one.
 func MergeAsciiSets(ss ...AsciiSet) (m AsciiSet) {
+	var tmp AsciiSet
 	for _, s := range ss {
-		s.EnumerateRanges(func(rmin, rmax byte) {
-			m.InsertRange(rmin, rmax)
-		})
+		tmp = UnionInto(tmp, m, s)
+		m, tmp = tmp, m
 	}
 	return
 }
@@ -250,29 +294,13 @@ func MergeAsciiSets(ss ...AsciiSet) (m AsciiSet) {
 // CountElements returns the number of ASCII codeunits effectively
 // contained in s.
 func (s AsciiSet) CountElements() int {
-	r, i, n := 0, 0, len(s)
-	for i+1 < n {
-		r += int(s[i+1]) - int(s[i])
-		i += 2
-	}
-	if i < n {
-		r += 128 - int(s[i])
-	}
-	return r
+	return int(domain_count(AsciiDomain, s))
 }
 
 // CountElements returns the number of unicode codepoints effectively
 // contained in s.
 func (s RuneSet) CountElements() int {
-	r, i, n := 0, 0, len(s)
-	for i+1 < n {
-		r += int(s[i+1]) - int(s[i])
-		i += 2
-	}
-	if i < n {
-		r += utf8.MaxRune - int(s[i])
-	}
-	return r
+	return int(domain_count(RuneDomain, s))
 }
 
 // Hull returns a hull of all the ranges in s.
@@ -297,6 +325,9 @@ func print_ascii(w *bytes.Buffer, c byte) {
 		w.WriteString("\\r")
 	case '\t':
 		w.WriteString("\\t")
+	case '\\', '-':
+		w.WriteByte('\\')
+		w.WriteByte(c)
 	default:
 		if c >= 0x80 {
 			w.WriteString("#INVALID")
@@ -309,7 +340,9 @@ func print_ascii(w *bytes.Buffer, c byte) {
 	}
 }
 
-// String produces a human-readable string with ranges and elements.
+// String produces a human-readable string with ranges and elements. Literal
+// backslashes and dashes are escaped, so the result can be parsed back with
+// ParseAsciiSet in the Plain dialect.
 func (a AsciiSet) String() string {
 	w := bytes.Buffer{}
 	a.EnumerateRanges(func(cmin, cmax byte) {
@@ -73,6 +73,9 @@ func TestRuneSet_String(t *testing.T) {
 		{RuneSet{'a', 'z' + 1}, `a-z`},
 		{RuneSet{'α', 'ω' + 1}, `\u03B1-\u03C9`},
 		{RuneSet{0x00, 0x7f + 1}, `\x00-\x7F`},
+		{RuneSet{'*', '-' + 1, '\\', '\\' + 1}, `*-\-\\`},
+		{RuneSet{'-', '/' + 1}, `\--/`},
+		{RuneSet{'!', '!' + 1, '-', '-' + 1, 'a', 'a' + 1}, `!\-a`},
 	}
 	for _, tt := range tests {
 		t.Run(tt.want, func(t *testing.T) {
@@ -87,6 +90,24 @@ func TestRuneSet_String(t *testing.T) {
 	}
 }
 
+func TestAsciiSet_String(t *testing.T) {
+	tests := []struct {
+		s    AsciiSet
+		want string
+	}{
+		{AsciiSet{}, ""},
+		{AsciiSet{'0', '9' + 1}, `0-9`},
+		{AsciiSet{'!', '!' + 1, '-', '-' + 1, 'a', 'a' + 1}, `!\-a`},
+		{AsciiSet{'*', '-' + 1, '\\', '\\' + 1}, `*-\-\\`},
+		{AsciiSet{'-'}, `\--\x7F`},
+	}
+	for _, tt := range tests {
+		if got := tt.s.String(); got != tt.want {
+			t.Errorf("%q.String() = %v, want %v", string(tt.s), got, tt.want)
+		}
+	}
+}
+
 func TestAsciiSet_CountElements(t *testing.T) {
 	tests := []struct {
 		s    AsciiSet
@@ -130,3 +151,47 @@ func TestAsciiSet_Hull(t *testing.T) {
 		})
 	}
 }
+
+func TestAsciiSet_RemoveRange(t *testing.T) {
+	tests := []struct {
+		s          AsciiSet
+		cmin, cmax byte
+		want       AsciiSet
+	}{
+		{AsciiSet{}, 'a', 'z', AsciiSet{}},
+		{AsciiSet{'a', 'z' + 1}, 'a', 'z', AsciiSet{}},
+		{AsciiSet{'a', 'z' + 1}, 'c', 'x', AsciiSet{'a', 'c', 'y', 'z' + 1}},
+		{AsciiSet{0}, 0x7f, 0x7f, AsciiSet{0, 0x7f}},
+		{AsciiSet{0}, 'a', 0x7f, AsciiSet{0, 'a'}},
+		{AsciiSet{0}, 0, 0x7f, AsciiSet{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s.String(), func(t *testing.T) {
+			got := slices.Clone(tt.s)
+			got.RemoveRange(tt.cmin, tt.cmax)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AsciiSet[%v].RemoveRange(%q, %q) = %v, want %v", tt.s, tt.cmin, tt.cmax, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuneSet_CountElements(t *testing.T) {
+	tests := []struct {
+		s    RuneSet
+		want int
+	}{
+		{RuneSet{}, 0},
+		{RuneSet{0}, 0x110000},
+		{RuneSet{0x10ffff}, 1},
+		{RuneSet{'a', 'z' + 1}, 26},
+		{RuneSet{'a', 'z' + 1, 0x10fff0}, 26 + 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s.String(), func(t *testing.T) {
+			if got := tt.s.CountElements(); got != tt.want {
+				t.Errorf("RuneSet.CountElements() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
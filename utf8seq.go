@@ -0,0 +1,134 @@
+package ics
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ByteRange is an inclusive [Lo,Hi] range of byte values.
+type ByteRange struct {
+	Lo, Hi byte
+}
+
+// UTF8Sequence matches a UTF-8 encoded rune one byte at a time: the n-th byte
+// of the encoding must be within the n-th range of the sequence.
+type UTF8Sequence []ByteRange
+
+// UTF8Sequences is a list of byte range sequences that matches the UTF-8
+// encodings of a set of runes. The sequences are mutually exclusive and they
+// are sorted by their byte ranges.
+type UTF8Sequences []UTF8Sequence
+
+// UTF8Sequences translates s into byte range sequences that match exactly the
+// UTF-8 encodings of its runes, which is suitable for building byte-oriented
+// automata. Surrogate halves have no UTF-8 encoding, so they are excluded.
+func (s RuneSet) UTF8Sequences() UTF8Sequences {
+	var r UTF8Sequences
+	s.EnumerateRanges(func(rmin, rmax rune) {
+		if rmin < 0xd800 {
+			r = append_utf8_sequences(r, rmin, min(rmax, 0xd7ff))
+		}
+		if rmax > 0xdfff {
+			r = append_utf8_sequences(r, max(rmin, 0xe000), rmax)
+		}
+	})
+	return r
+}
+
+// append_utf8_sequences splits a valid [lo,hi] range of non-surrogate runes
+// into pieces that have encodings of the same length and that differ only in a
+// contiguous range of bytes at each position.
+func append_utf8_sequences(r UTF8Sequences, lo, hi rune) UTF8Sequences {
+	type piece struct{ lo, hi rune }
+	stack := []piece{{lo, hi}}
+
+next:
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// split at the boundaries of encoding lengths
+		for _, b := range []rune{0x7f, 0x7ff, 0xffff} {
+			if p.lo <= b && b < p.hi {
+				stack = append(stack, piece{b + 1, p.hi}, piece{p.lo, b})
+				continue next
+			}
+		}
+		if p.hi < utf8.RuneSelf {
+			r = append(r, UTF8Sequence{{byte(p.lo), byte(p.hi)}})
+			continue
+		}
+
+		// split until all continuation bytes below a differing byte span the
+		// full [80..BF] range
+		for i := 1; i < utf8.UTFMax; i++ {
+			m := rune(1)<<(6*i) - 1
+			if p.lo&^m == p.hi&^m {
+				continue
+			}
+			if p.lo&m != 0 {
+				stack = append(stack, piece{p.lo | m + 1, p.hi}, piece{p.lo, p.lo | m})
+				continue next
+			}
+			if p.hi&m != m {
+				stack = append(stack, piece{p.hi &^ m, p.hi}, piece{p.lo, p.hi&^m - 1})
+				continue next
+			}
+		}
+
+		var a, b [utf8.UTFMax]byte
+		n := utf8.EncodeRune(a[:], p.lo)
+		utf8.EncodeRune(b[:], p.hi)
+		seq := make(UTF8Sequence, n)
+		for i := range seq {
+			seq[i] = ByteRange{a[i], b[i]}
+		}
+		r = append(r, seq)
+	}
+	return r
+}
+
+// MatchUTF8 matches the leading UTF-8 encoded rune of b against the sequences
+// without decoding it. Returns the size of the matching encoding.
+func (ss UTF8Sequences) MatchUTF8(b []byte) (size int, ok bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	for _, s := range ss {
+		if b[0] < s[0].Lo {
+			// the remaining sequences start with greater bytes
+			break
+		}
+		if len(b) >= len(s) && s.match(b) {
+			return len(s), true
+		}
+	}
+	return 0, false
+}
+
+func (s UTF8Sequence) match(b []byte) bool {
+	for i, r := range s {
+		if b[i] < r.Lo || b[i] > r.Hi {
+			return false
+		}
+	}
+	return true
+}
+
+// String produces a human-readable form of the sequence, e.g.
+// [E1-EC][80-BF][80-BF].
+func (s UTF8Sequence) String() string {
+	w := strings.Builder{}
+	for _, r := range s {
+		w.WriteByte('[')
+		w.WriteByte(hex[r.Lo>>4])
+		w.WriteByte(hex[r.Lo&0xf])
+		if r.Hi != r.Lo {
+			w.WriteByte('-')
+			w.WriteByte(hex[r.Hi>>4])
+			w.WriteByte(hex[r.Hi&0xf])
+		}
+		w.WriteByte(']')
+	}
+	return w.String()
+}
@@ -0,0 +1,84 @@
+package ics
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestRuneSet_UTF8Sequences(t *testing.T) {
+	tests := []struct {
+		s    RuneSet
+		want string
+	}{
+		{RuneSet{}, ""},
+		{RuneSet{'a', 'z' + 1}, "[61-7A]"},
+		{RuneSet{0x80, 0x800}, "[C2-DF][80-BF]"},
+		{RuneSet{0x7f, 0x81}, "[7F] [C2][80]"},
+		{RuneSet{0xd7ff, 0xe001}, "[ED][9F][BF] [EE][80][80]"},
+		{RuneSet{0}, strings.Join([]string{
+			"[00-7F]",
+			"[C2-DF][80-BF]",
+			"[E0][A0-BF][80-BF]",
+			"[E1-EC][80-BF][80-BF]",
+			"[ED][80-9F][80-BF]",
+			"[EE-EF][80-BF][80-BF]",
+			"[F0][90-BF][80-BF][80-BF]",
+			"[F1-F3][80-BF][80-BF][80-BF]",
+			"[F4][80-8F][80-BF][80-BF]",
+		}, " ")},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, seq := range tt.s.UTF8Sequences() {
+			got = append(got, seq.String())
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("%v.UTF8Sequences() = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestUTF8Sequences_MatchUTF8(t *testing.T) {
+	sets := []RuneSet{
+		{0},
+		FromRangeTable(unicode.L),
+		FromRangeTable(unicode.Cs).Inverted(),
+	}
+	for i := 0; i < 20; i++ {
+		sets = append(sets, random_runeset(rand.Intn(16)))
+	}
+	for _, s := range sets {
+		ss := s.UTF8Sequences()
+		check := func(r rune) {
+			if r < 0 || r > unicode.MaxRune || (r >= 0xd800 && r <= 0xdfff) {
+				return
+			}
+			b := utf8.AppendRune(nil, r)
+			size, ok := ss.MatchUTF8(append(b, 0x80))
+			if ok != s.Contains(r) || (ok && size != len(b)) {
+				t.Fatalf("%v: MatchUTF8(%U) = %d, %v", s, r, size, ok)
+			}
+			if _, ok := ss.MatchUTF8(b[:len(b)-1]); ok {
+				t.Fatalf("%v: MatchUTF8 matches a truncated %U", s, r)
+			}
+		}
+		for _, r := range s {
+			for d := rune(-2); d <= 2; d++ {
+				check(r + d)
+			}
+		}
+		for i := 0; i < 1000; i++ {
+			check(rand.Int31n(unicode.MaxRune + 1))
+		}
+
+		// invalid encodings: overlong, surrogate, beyond MaxRune
+		for _, b := range []string{"\xc0\x80", "\xe0\x80\x80", "\xed\xa0\x80", "\xf4\x90\x80\x80", "\x80", ""} {
+			if _, ok := ss.MatchUTF8([]byte(b)); ok {
+				t.Fatalf("%v: MatchUTF8(%q) succeeds", s, b)
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package ics
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/constraints"
+)
+
+// InvalidSetError describes the first problem found by Validate.
+type InvalidSetError struct {
+	Index  int    // index of the offending boundary
+	Reason string // description of the problem
+}
+
+func (e *InvalidSetError) Error() string {
+	return fmt.Sprintf("ics: invalid set at index %d: %s", e.Index, e.Reason)
+}
+
+// Validate checks that s is a well-formed flattened set: its boundaries must
+// be strictly increasing, which also rules out duplicates and NaN values.
+// Returns an *InvalidSetError for the first offending boundary.
+func Validate[S ~[]T, T constraints.Ordered](s S) error {
+	for i := range s {
+		if err := validate_at(s, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate_at checks the order of s[i] relative to its predecessor.
+func validate_at[S ~[]T, T constraints.Ordered](s S, i int) error {
+	switch v := s[i]; {
+	case v != v:
+		return &InvalidSetError{i, "NaN value"}
+	case i == 0:
+		return nil
+	case v == s[i-1]:
+		return &InvalidSetError{i, "duplicate value"}
+	case v < s[i-1]:
+		return &InvalidSetError{i, "unsorted value"}
+	}
+	return nil
+}
+
+// domain_validate is Validate that also checks that all boundaries are within
+// the domain.
+func domain_validate[S ~[]T, T constraints.Integer](d Domain[T], s S) error {
+	for i, v := range s {
+		if !d.Contains(v) {
+			return &InvalidSetError{i, fmt.Sprintf("value %v is outside of the domain [%v..%v]", v, d.Min, d.Max)}
+		}
+		if err := validate_at(s, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Normalize repairs an arbitrary slice into a canonical set. The elements of s
+// are treated as a sequence of intervals [s[0],s[1]), [s[2],s[3]) and so on,
+// which may be unsorted and overlapping, with an open-ended interval for the
+// trailing element of an odd-sized slice. Reversed intervals are swapped,
+// while empty intervals and intervals with NaN values are discarded.
+func Normalize[S ~[]T, T constraints.Ordered](s S) S {
+	b := Builder[T]{}
+	for i := 0; i < len(s); i += 2 {
+		l := s[i]
+		if l != l {
+			continue
+		}
+		if i+1 == len(s) {
+			b.Add(l, l)
+			break
+		}
+		h := s[i+1]
+		if h != h || h == l {
+			continue
+		}
+		if h < l {
+			l, h = h, l
+		}
+		b.Add(l, h)
+	}
+	return S(b.Build())
+}
+
+// domain_normalize is Normalize that also clips the result to the domain.
+func domain_normalize[S ~[]T, T constraints.Integer](d Domain[T], s S) S {
+	r := Intersect(Normalize(s), S{d.Min})
+	if n := len(r); n > 0 && r[n-1] > d.Max {
+		// a value beyond Max exists, so Max+1 does not overflow
+		r = Intersect(r, S{d.Min, d.Max + 1})
+		if n = len(r); n > 0 && r[n-1] == d.Max+1 {
+			r = r[:n-1]
+		}
+	}
+	return r
+}
+
+// Validate checks that s is a well-formed set, see Validate.
+func (s Set[T]) Validate() error {
+	return Validate(s)
+}
+
+// Validate checks that s is a well-formed set with boundaries within
+// RuneDomain.
+func (s RuneSet) Validate() error {
+	return domain_validate(RuneDomain, s)
+}
+
+// Validate checks that s is a well-formed set with boundaries within
+// AsciiDomain.
+func (s AsciiSet) Validate() error {
+	return domain_validate(AsciiDomain, s)
+}
+
+// Validate checks that s is a well-formed set with boundaries within its
+// domain.
+func (s DomainSet[T]) Validate() error {
+	return domain_validate(s.Domain, s.Set)
+}
+
+// Normalize repairs s into a canonical set, see Normalize.
+func (s Set[T]) Normalize() Set[T] {
+	return Normalize(s)
+}
+
+// Normalize repairs s into a canonical set, see Normalize. Values outside of
+// RuneDomain are discarded.
+func (s RuneSet) Normalize() RuneSet {
+	return domain_normalize(RuneDomain, s)
+}
+
+// Normalize repairs s into a canonical set, see Normalize. Values outside of
+// AsciiDomain are discarded.
+func (s AsciiSet) Normalize() AsciiSet {
+	return domain_normalize(AsciiDomain, s)
+}
+
+// Normalize repairs s into a canonical set, see Normalize. Values outside of
+// the domain are discarded.
+func (s DomainSet[T]) Normalize() DomainSet[T] {
+	return DomainSet[T]{s.Domain, domain_normalize(s.Domain, s.Set)}
+}
@@ -0,0 +1,116 @@
+package ics
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+	"unicode"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		s      Set[float64]
+		index  int
+		reason string
+	}{
+		{Set[float64]{}, -1, ""},
+		{Set[float64]{1, 2, 3}, -1, ""},
+		{Set[float64]{5, 3, 3}, 1, "unsorted value"},
+		{Set[float64]{1, 3, 3}, 2, "duplicate value"},
+		{Set[float64]{1, math.NaN()}, 1, "NaN value"},
+		{Set[float64]{math.NaN()}, 0, "NaN value"},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.s)
+		var e *InvalidSetError
+		if tt.index < 0 {
+			if err != nil {
+				t.Errorf("Validate(%v) = %v, want nil", tt.s, err)
+			}
+		} else if !errors.As(err, &e) || e.Index != tt.index || e.Reason != tt.reason {
+			t.Errorf("Validate(%v) = %v, want index %d: %s", tt.s, err, tt.index, tt.reason)
+		}
+	}
+}
+
+func TestRuneSet_Validate(t *testing.T) {
+	tests := []struct {
+		s     RuneSet
+		index int
+	}{
+		{RuneSet{'a', 'z' + 1, unicode.MaxRune}, -1},
+		{RuneSet{5, 3, 3}, 1},
+		{RuneSet{-1, 3}, 0},
+		{RuneSet{'a', unicode.MaxRune + 1}, 1},
+		{RuneSet{'a', 'b', 'b'}, 2},
+	}
+	for _, tt := range tests {
+		err := tt.s.Validate()
+		var e *InvalidSetError
+		if tt.index < 0 && err != nil {
+			t.Errorf("%v.Validate() = %v, want nil", Set[rune](tt.s), err)
+		} else if tt.index >= 0 && (!errors.As(err, &e) || e.Index != tt.index) {
+			t.Errorf("%v.Validate() = %v, want index %d", Set[rune](tt.s), err, tt.index)
+		}
+	}
+	if err := (AsciiSet{'a', 0x80}).Validate(); err == nil {
+		t.Error("AsciiSet{'a', 0x80}.Validate() succeeds")
+	}
+	if err := (DomainSet[int]{NewDomain(0, 9), Set[int]{3, 10}}).Validate(); err == nil {
+		t.Error("DomainSet.Validate() succeeds for a value beyond Max")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		s, want Set[float64]
+	}{
+		{nil, Set[float64]{}},
+		{Set[float64]{5, 3, 3}, Set[float64]{3}},
+		{Set[float64]{8, 9, 1, 5, 3, 8}, Set[float64]{1, 9}},
+		{Set[float64]{1, 1, 2, math.NaN(), 4, 6}, Set[float64]{4, 6}},
+		{Set[float64]{1, 2, math.NaN()}, Set[float64]{1, 2}},
+		{Set[float64]{1, 2, 7, 9, 8}, Set[float64]{1, 2, 7}},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.s); !got.Equal(tt.want) {
+			t.Errorf("Normalize(%v) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+
+	runes := []struct {
+		s, want RuneSet
+	}{
+		{RuneSet{-5, 3}, RuneSet{0, 3}},
+		{RuneSet{'a', unicode.MaxRune + 1}, RuneSet{'a'}},
+		{RuneSet{unicode.MaxRune + 1, unicode.MaxRune + 5}, RuneSet{}},
+		{RuneSet{'z' + 1, 'a', '0', '9' + 1}, RuneSet{'0', '9' + 1, 'a', 'z' + 1}},
+	}
+	for _, tt := range runes {
+		if got := tt.s.Normalize(); !got.Equal(tt.want) {
+			t.Errorf("%v.Normalize() = %v, want %v", Set[rune](tt.s), Set[rune](got), Set[rune](tt.want))
+		}
+	}
+	if got := (AsciiSet{'a', 0xff}).Normalize(); !got.Equal(AsciiSet{'a'}) {
+		t.Errorf("AsciiSet.Normalize() = %v", Set[byte](got))
+	}
+
+	for i := 0; i < 500; i++ {
+		s := make(Set[int8], rand.Intn(8))
+		for j := range s {
+			s[j] = int8(rand.Intn(256) - 128)
+		}
+		got := Normalize(s)
+		if err := Validate(got); err != nil {
+			t.Fatalf("Normalize(%v) = %v: %v", s, got, err)
+		}
+		if Validate(s) == nil && !got.Equal(s) {
+			t.Fatalf("Normalize(%v) = %v, want the same set", s, got)
+		}
+		d := DomainSet[int8]{NewDomain[int8](-10, 10), s}.Normalize()
+		if err := d.Validate(); err != nil {
+			t.Fatalf("DomainSet.Normalize(%v) = %v: %v", s, d.Set, err)
+		}
+	}
+}